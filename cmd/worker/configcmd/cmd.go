@@ -0,0 +1,163 @@
+package configcmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/artefactual-sdps/preprocessing-base/cmd/worker/workercmd"
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+const Name = "config"
+
+// Main checks and prints the worker configuration, to find configuration
+// errors before deploying the worker.
+type Main struct {
+	out io.Writer
+}
+
+func NewMain(out io.Writer) *Main {
+	return &Main{out: out}
+}
+
+// Validate reads and validates the configuration, writing each configuration
+// error on its own line. It returns an error if the configuration isn't valid.
+func (m *Main) Validate(configFile string) error {
+	var cfg config.Configuration
+	_, configFileUsed, err := config.Read(&cfg, configFile)
+	if err == nil {
+		err = workercmd.CheckSteps(cfg)
+	}
+	if err != nil {
+		for _, line := range errorLines(err) {
+			fmt.Fprintln(m.out, line)
+		}
+		return errors.New("config: invalid configuration")
+	}
+
+	fmt.Fprintf(m.out, "OK   %s\n", configFileUsed)
+
+	return nil
+}
+
+// Print writes the effective configuration as TOML, commenting the source of
+// each value and with the secret values redacted.
+func (m *Main) Print(configFile string) error {
+	settings, configFileUsed, err := config.Describe(configFile)
+	if err != nil {
+		return fmt.Errorf("config: %v", err)
+	}
+
+	fmt.Fprintf(m.out, "# Configuration file: %s\n", configFileUsed)
+
+	// The top-level settings must be written before the tables, and the
+	// arrays of tables last.
+	sort.SliceStable(settings, func(i, j int) bool {
+		return rank(settings[i]) < rank(settings[j])
+	})
+
+	w := tabwriter.NewWriter(m.out, 0, 0, 1, ' ', 0)
+	var section string
+	for _, s := range settings {
+		key := config.TOMLKey(s.Key)
+		if rank(s) == 2 {
+			rv := reflect.ValueOf(s.Value)
+			for i := range rv.Len() {
+				_ = w.Flush()
+				fmt.Fprintf(m.out, "\n[[%s]]\n", key)
+				m.writeTable(w, key, rv.Index(i), s.Source)
+			}
+			continue
+		}
+		if i := strings.LastIndex(key, "."); i >= 0 {
+			if key[:i] != section {
+				section = key[:i]
+				_ = w.Flush()
+				fmt.Fprintf(m.out, "\n[%s]\n", section)
+			}
+			key = key[i+1:]
+		}
+		fmt.Fprintf(w, "%s = %s\t# %s\n", key, config.TOMLValue(s.Value), s.Source)
+	}
+
+	return w.Flush()
+}
+
+// writeTable writes the fields of the struct v, the value of the table name,
+// with the nested structs as sub-tables.
+func (m *Main) writeTable(w *tabwriter.Writer, name string, v reflect.Value, source config.Source) {
+	var tables []int
+	for i := range v.NumField() {
+		if v.Field(i).Kind() == reflect.Struct {
+			tables = append(tables, i)
+			continue
+		}
+		key := config.TOMLKey(v.Type().Field(i).Name)
+		fmt.Fprintf(w, "%s = %s\t# %s\n", key, config.TOMLValue(v.Field(i).Interface()), source)
+	}
+
+	for _, i := range tables {
+		key := name + "." + config.TOMLKey(v.Type().Field(i).Name)
+		_ = w.Flush()
+		fmt.Fprintf(m.out, "\n[%s]\n", key)
+		m.writeTable(w, key, v.Field(i), source)
+	}
+}
+
+// rank orders the settings for writing: 0 for top-level settings, 1 for the
+// settings in tables and 2 for arrays of tables.
+func rank(s config.Setting) int {
+	if strings.Contains(s.Key, ".") {
+		return 1
+	}
+	if t := reflect.TypeOf(s.Value); t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Struct {
+		return 2
+	}
+
+	return 0
+}
+
+// Defaults writes a commented TOML configuration file with the default values.
+func (m *Main) Defaults() error {
+	_, err := io.WriteString(m.out, config.DefaultsTemplate)
+
+	return err
+}
+
+// Schema writes the JSON Schema of the configuration file, or its Markdown
+// reference if format is "markdown".
+func (m *Main) Schema(format string) error {
+	switch format {
+	case "json":
+		b, err := json.MarshalIndent(config.Schema(), "", "  ")
+		if err != nil {
+			return fmt.Errorf("config: %v", err)
+		}
+		_, err = fmt.Fprintf(m.out, "%s\n", b)
+		return err
+	case "markdown":
+		_, err := io.WriteString(m.out, config.Reference())
+		return err
+	default:
+		return fmt.Errorf("config: unknown schema format %q (json, markdown)", format)
+	}
+}
+
+// errorLines returns the messages of the errors joined in err.
+func errorLines(err error) []string {
+	if u, ok := err.(interface{ Unwrap() []error }); ok {
+		var lines []string
+		for _, e := range u.Unwrap() {
+			lines = append(lines, errorLines(e)...)
+		}
+		return lines
+	}
+
+	return []string{err.Error()}
+}
@@ -0,0 +1,216 @@
+package configcmd_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/artefactual-sdps/preprocessing-base/cmd/worker/configcmd"
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+const testConfig = `sharedPath = "/home/preprocessing/shared"
+[temporal]
+taskQueue = "preprocessing"
+workflowName = "preprocessing"
+[database]
+enabled = true
+dsn = "/home/preprocessing/preprocessing.db"
+[messages.catalogs]
+fr = "testdata/fr.json"
+[[profiles]]
+name = "donor-a"
+taskQueue = "preprocessing-donor-a"
+workflowName = "preprocessing-donor-a"
+steps = ["bag-sip"]
+[profiles.bagit]
+checksumAlgorithm = "sha256"
+`
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Validates a configuration file", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := fs.NewDir(t, "preprocessing-test", fs.WithFile("preprocessing.toml", testConfig))
+
+		var out bytes.Buffer
+		err := configcmd.NewMain(&out).Validate(tmpDir.Join("preprocessing.toml"))
+		assert.NilError(t, err)
+		assert.Equal(t, out.String(), "OK   "+tmpDir.Join("preprocessing.toml")+"\n")
+	})
+
+	t.Run("Prints each configuration error", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := fs.NewDir(t, "preprocessing-test", fs.WithFile("preprocessing.toml", `[worker]
+maxConcurrentSessions = 0
+`))
+
+		var out bytes.Buffer
+		err := configcmd.NewMain(&out).Validate(tmpDir.Join("preprocessing.toml"))
+		assert.Error(t, err, "config: invalid configuration")
+		assert.Equal(t, out.String(), `invalid configuration:
+SharedPath: missing required value
+Temporal.TaskQueue: missing required value
+Temporal.WorkflowName: missing required value
+Worker.MaxConcurrentSessions: 0 is less than the minimum value (1)
+`)
+	})
+
+	t.Run("Prints the unknown workflow steps", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := fs.NewDir(t, "preprocessing-test", fs.WithFile("preprocessing.toml", `sharedPath = "/home/preprocessing/shared"
+[temporal]
+taskQueue = "preprocessing"
+workflowName = "preprocessing"
+[[profiles]]
+name = "donor-a"
+taskQueue = "donor-a"
+workflowName = "donor-a"
+steps = ["virus-scan"]
+`))
+
+		var out bytes.Buffer
+		err := configcmd.NewMain(&out).Validate(tmpDir.Join("preprocessing.toml"))
+		assert.Error(t, err, "config: invalid configuration")
+		assert.Equal(
+			t,
+			out.String(),
+			`Profiles[0].Steps: "virus-scan" is not a workflow step (bag-sip, describe-sip)`+"\n",
+		)
+	})
+
+	t.Run("Prints the read error", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := fs.NewDir(t, "preprocessing-test")
+
+		var out bytes.Buffer
+		err := configcmd.NewMain(&out).Validate(tmpDir.Join("missing.toml"))
+		assert.Error(t, err, "config: invalid configuration")
+		assert.Equal(t, out.String(), "configuration file not found: "+tmpDir.Join("missing.toml")+"\n")
+	})
+}
+
+func TestPrint(t *testing.T) {
+	t.Setenv("ENDURO_PREPROCESSING_VERBOSITY", "2")
+	t.Setenv("ENDURO_PREPROCESSING_TEMPORAL_APIKEY", "secret")
+
+	tmpDir := fs.NewDir(t, "preprocessing-test", fs.WithFile("preprocessing.toml", testConfig))
+
+	var out bytes.Buffer
+	err := configcmd.NewMain(&out).Print(tmpDir.Join("preprocessing.toml"))
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), `# Configuration file: `+tmpDir.Join("preprocessing.toml")+`
+debug = false                             # default
+verbosity = 2                             # env
+sharedPath = "/home/preprocessing/shared" # file
+organization = ""                         # default
+steps = []                                # default
+
+[temporal]
+address = "localhost:7233"                # default
+namespace = "default"                     # default
+taskQueue = "preprocessing"               # file
+workflowName = "preprocessing"            # file
+batchWorkflowName = "preprocessing-batch" # default
+apiKey = "********"                       # env
+
+[temporal.tls]
+enabled = false # default
+caCert = ""     # default
+clientCert = "" # default
+clientKey = ""  # default
+serverName = "" # default
+
+[worker]
+maxConcurrentSessions = 1 # default
+maxBatchConcurrency = 5   # default
+minFreeSpace = "0 B"      # default
+activityTimeout = "5m0s"  # default
+
+[bagit]
+checksumAlgorithm = "" # default
+
+[eventLog]
+enabled = false                     # default
+path = "preprocessing-events.jsonl" # default
+signalParent = false                # default
+
+[database]
+enabled = true    # file
+driver = "sqlite" # default
+dsn = "********"  # file
+
+[messages]
+locale = "en"                          # default
+catalogs = {"fr" = "testdata/fr.json"} # file
+
+[log]
+format = ""      # default
+file = ""        # default
+maxSize = 100    # default
+maxBackups = 0   # default
+maxAge = 0       # default
+compress = false # default
+verbosity = {}   # default
+
+[[profiles]]
+name = "donor-a"                       # file
+taskQueue = "preprocessing-donor-a"    # file
+workflowName = "preprocessing-donor-a" # file
+batchWorkflowName = ""                 # file
+steps = ["bag-sip"]                    # file
+
+[profiles.bagit]
+checksumAlgorithm = "sha256" # file
+`)
+}
+
+func TestDefaults(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	err := configcmd.NewMain(&out).Defaults()
+	assert.NilError(t, err)
+	assert.Equal(t, out.String(), config.DefaultsTemplate)
+}
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Writes the JSON Schema", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		err := configcmd.NewMain(&out).Schema("json")
+		assert.NilError(t, err)
+
+		var s map[string]any
+		assert.NilError(t, json.Unmarshal(out.Bytes(), &s))
+		assert.Equal(t, s["title"], "Preprocessing worker configuration")
+	})
+
+	t.Run("Writes the Markdown reference", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		err := configcmd.NewMain(&out).Schema("markdown")
+		assert.NilError(t, err)
+		assert.Equal(t, out.String(), config.Reference())
+	})
+
+	t.Run("Errors on an unknown format", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		err := configcmd.NewMain(&out).Schema("yaml")
+		assert.Error(t, err, `config: unknown schema format "yaml" (json, markdown)`)
+	})
+}
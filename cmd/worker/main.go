@@ -9,16 +9,25 @@ import (
 	"runtime"
 
 	"github.com/spf13/pflag"
-	"go.artefactual.dev/tools/log"
 
+	"github.com/artefactual-sdps/preprocessing-base/cmd/worker/configcmd"
+	"github.com/artefactual-sdps/preprocessing-base/cmd/worker/replaycmd"
 	"github.com/artefactual-sdps/preprocessing-base/cmd/worker/workercmd"
 	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+	"github.com/artefactual-sdps/preprocessing-base/internal/logging"
 	"github.com/artefactual-sdps/preprocessing-base/internal/version"
 )
 
 const appName = "preprocessing-base-worker"
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == replaycmd.Name {
+		os.Exit(replay(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == configcmd.Name {
+		os.Exit(configCommand(os.Args[2:]))
+	}
+
 	p := pflag.NewFlagSet(workercmd.Name, pflag.ExitOnError)
 	p.String("config", "", "Configuration file")
 	p.Bool("version", false, "Show version information")
@@ -42,12 +51,12 @@ func main() {
 		os.Exit(1)
 	}
 
-	logger := log.New(os.Stderr,
-		log.WithName(workercmd.Name),
-		log.WithDebug(cfg.Debug),
-		log.WithLevel(cfg.Verbosity),
-	)
-	defer log.Sync(logger)
+	logger, closeLog, err := logging.New(os.Stderr, workercmd.Name, cfg)
+	if err != nil {
+		fmt.Printf("Failed to create logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer closeLog()
 
 	keys := []interface{}{
 		"version", version.Long,
@@ -77,6 +86,10 @@ func main() {
 		os.Exit(1)
 	}
 
+	if configFileFound {
+		config.Watch(configFileUsed, m.Reload)
+	}
+
 	<-ctx.Done()
 
 	if err := m.Close(); err != nil {
@@ -85,3 +98,91 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// replay runs the replay subcommand with the given arguments and returns the
+// process exit code.
+func replay(args []string) int {
+	p := pflag.NewFlagSet(workercmd.Name+" "+replaycmd.Name, pflag.ExitOnError)
+	p.String("config", "", "Configuration file")
+	p.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s [flags] <history.json|dir>...\n", workercmd.Name, replaycmd.Name)
+		fmt.Fprintln(os.Stderr, "Replay Temporal workflow histories against the registered workflows.")
+		fmt.Fprintln(os.Stderr, "Replay needs the same eventLog and database settings as the production worker,")
+		fmt.Fprintln(os.Stderr, "the workflows started by older worker versions replay with them.")
+		p.PrintDefaults()
+	}
+	if err := p.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	var cfg config.Configuration
+	configFile, _ := p.GetString("config")
+	if _, _, err := config.Read(&cfg, configFile); err != nil {
+		fmt.Printf("Failed to read configuration: %v\n", err)
+		return 1
+	}
+
+	logger, closeLog, err := logging.New(os.Stderr, workercmd.Name, cfg)
+	if err != nil {
+		fmt.Printf("Failed to create logger: %v\n", err)
+		return 1
+	}
+	defer closeLog()
+
+	if err := replaycmd.NewMain(logger, cfg, os.Stdout).Run(p.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+// configCommand runs the config subcommand with the given arguments and
+// returns the process exit code.
+func configCommand(args []string) int {
+	p := pflag.NewFlagSet(workercmd.Name+" "+configcmd.Name, pflag.ExitOnError)
+	p.String("config", "", "Configuration file")
+	p.String("format", "json", "Schema format: json or markdown")
+	p.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s <validate|print|defaults|schema> [flags]\n", workercmd.Name, configcmd.Name)
+		fmt.Fprintln(os.Stderr, "  validate  Validate the configuration and print its errors.")
+		fmt.Fprintln(os.Stderr, "  print     Print the effective configuration and the source of its values.")
+		fmt.Fprintln(os.Stderr, "  defaults  Print a configuration file with the default values.")
+		fmt.Fprintln(os.Stderr, "  schema    Print the JSON Schema or the Markdown reference of the configuration.")
+		p.PrintDefaults()
+	}
+	if err := p.Parse(args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if p.NArg() != 1 {
+		p.Usage()
+		return 1
+	}
+
+	m := configcmd.NewMain(os.Stdout)
+	configFile, _ := p.GetString("config")
+
+	var err error
+	switch p.Arg(0) {
+	case "validate":
+		err = m.Validate(configFile)
+	case "print":
+		err = m.Print(configFile)
+	case "defaults":
+		err = m.Defaults()
+	case "schema":
+		format, _ := p.GetString("format")
+		err = m.Schema(format)
+	default:
+		p.Usage()
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,206 @@
+package replaycmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"go.artefactual.dev/tools/temporal"
+	temporalapi_enums "go.temporal.io/api/enums/v1"
+	temporalapi_history "go.temporal.io/api/history/v1"
+	temporalsdk_client "go.temporal.io/sdk/client"
+	temporalsdk_worker "go.temporal.io/sdk/worker"
+
+	"github.com/artefactual-sdps/preprocessing-base/cmd/worker/workercmd"
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+const Name = "replay"
+
+// Main replays exported workflow histories against the registered workflows
+// to find nondeterministic changes in the workflow code without the need of a
+// Temporal server.
+type Main struct {
+	logger logr.Logger
+	cfg    config.Configuration
+	out    io.Writer
+}
+
+func NewMain(logger logr.Logger, cfg config.Configuration, out io.Writer) *Main {
+	return &Main{
+		logger: logger,
+		cfg:    cfg,
+		out:    out,
+	}
+}
+
+// Run replays the workflow history JSON files found in paths, paths can be
+// history files or directories containing them. It writes the result of each
+// replay to the output and returns an error if any of the replays failed.
+func (m *Main) Run(paths []string) error {
+	if len(paths) == 0 {
+		return errors.New("replay: missing workflow history path")
+	}
+
+	files, err := historyFiles(paths)
+	if err != nil {
+		return fmt.Errorf("replay: %v", err)
+	}
+	if len(files) == 0 {
+		return errors.New("replay: no workflow history files found")
+	}
+
+	var failed int
+	for _, f := range files {
+		if err := m.replayFile(f); err != nil {
+			failed++
+			fmt.Fprintf(m.out, "FAIL %s: %v\n", f, err)
+			continue
+		}
+		fmt.Fprintf(m.out, "OK   %s\n", f)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("replay: %d of %d workflow histories failed", failed, len(files))
+	}
+
+	return nil
+}
+
+func (m *Main) replayFile(path string) error {
+	f, err := os.Open(path) // #nosec G304 -- path provided by the user.
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	history, err := temporalsdk_client.HistoryFromJSON(f, temporalsdk_client.HistoryJSONOptions{})
+	if err != nil {
+		return fmt.Errorf("load history: %v", err)
+	}
+
+	if err := m.replay(history); err != nil {
+		switch id := m.lastReplayedEventID(history); {
+		case id > 0:
+			return fmt.Errorf("replayed cleanly up to event ID %d: %v", id, err)
+		case id == 0:
+			return fmt.Errorf("failed in the first workflow task: %v", err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (m *Main) replay(history *temporalapi_history.History) error {
+	replayer := temporalsdk_worker.NewWorkflowReplayer()
+	m.registerWorkflows(replayer)
+
+	return replayer.ReplayWorkflowHistory(temporal.Logger(m.logger.WithName("temporal")), history)
+}
+
+// registerWorkflows registers the workflows of the top-level configuration and
+// of each profile. The profiles can reuse the workflow names of other task
+// queues, only the first configuration using a name is registered.
+func (m *Main) registerWorkflows(r temporalsdk_worker.WorkflowRegistry) {
+	registered := map[string]bool{}
+	for _, pc := range m.cfg.ProfileConfigs() {
+		if registered[pc.Temporal.WorkflowName] {
+			continue
+		}
+		registered[pc.Temporal.WorkflowName] = true
+
+		if registered[pc.Temporal.BatchWorkflowName] {
+			pc.Temporal.BatchWorkflowName = ""
+		} else if pc.Temporal.BatchWorkflowName != "" {
+			registered[pc.Temporal.BatchWorkflowName] = true
+		}
+
+		// The event messages don't change the workflow commands, replay them
+		// with the English catalog.
+		workercmd.RegisterWorkflows(r, config.NewStore(pc), nil)
+	}
+}
+
+// lastReplayedEventID returns the ID of the last event of the longest part
+// of history, ending with the commands of a workflow task, that replays
+// without errors. It returns zero if the first workflow task can't be
+// replayed, or -1 if all of them can.
+//
+// The SDK replay errors don't include the ID of the mismatched event, so the
+// workflow tasks that replay are searched replaying parts of the history, a
+// part replays only if the parts it contains replay too.
+func (m *Main) lastReplayedEventID(history *temporalapi_history.History) int64 {
+	events := history.GetEvents()
+
+	// ends are the lengths of the history parts ending with the commands of
+	// each completed workflow task.
+	var ends []int
+	var completed bool
+	for i, e := range events {
+		switch e.GetEventType() {
+		case temporalapi_enums.EVENT_TYPE_WORKFLOW_TASK_COMPLETED:
+			completed = true
+		case temporalapi_enums.EVENT_TYPE_WORKFLOW_TASK_SCHEDULED:
+			// A new workflow task is scheduled, the commands of the previous
+			// one are in the history.
+			if completed {
+				ends = append(ends, i)
+				completed = false
+			}
+		}
+	}
+	if completed {
+		ends = append(ends, len(events))
+	}
+
+	n := sort.Search(len(ends), func(i int) bool {
+		return m.replay(&temporalapi_history.History{Events: events[:ends[i]]}) != nil
+	})
+	switch n {
+	case 0:
+		return 0
+	case len(ends):
+		return -1
+	}
+
+	return events[ends[n-1]-1].GetEventId()
+}
+
+// historyFiles returns the list of history files in paths, expanding the
+// directories to the JSON files they contain.
+func historyFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		fi, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			files = append(files, p)
+			continue
+		}
+
+		entries, err := os.ReadDir(p)
+		if err != nil {
+			return nil, err
+		}
+
+		var dirFiles []string
+		for _, e := range entries {
+			if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".json") {
+				continue
+			}
+			dirFiles = append(dirFiles, filepath.Join(p, e.Name()))
+		}
+		sort.Strings(dirFiles)
+		files = append(files, dirFiles...)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,141 @@
+package replaycmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/artefactual-sdps/preprocessing-base/cmd/worker/replaycmd"
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+var (
+	historyPath      = filepath.Join("..", "..", "..", "internal", "workflow", "testdata", "preprocessing_success.json")
+	sinksHistoryPath = filepath.Join("..", "..", "..", "internal", "workflow", "testdata", "preprocessing_sinks.json")
+)
+
+func testConfig() config.Configuration {
+	return config.Configuration{
+		SharedPath: "/home/preprocessing/shared",
+		Temporal: config.Temporal{
+			TaskQueue:         "preprocessing",
+			WorkflowName:      "preprocessing",
+			BatchWorkflowName: "preprocessing-batch",
+		},
+		Worker: config.WorkerConfig{
+			MaxConcurrentSessions: 1,
+			MaxBatchConcurrency:   1,
+		},
+	}
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	history, err := os.ReadFile(historyPath)
+	assert.NilError(t, err)
+
+	// Replace the activity type to make the history nondeterministic.
+	changed := strings.Replace(string(history), `"name": "bag-create"`, `"name": "other-activity"`, 1)
+
+	t.Run("Replays a history file", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		err := replaycmd.NewMain(logr.Discard(), testConfig(), &out).Run([]string{historyPath})
+		assert.NilError(t, err)
+		assert.Equal(t, out.String(), "OK   "+historyPath+"\n")
+	})
+
+	t.Run("Replays a directory of history files", func(t *testing.T) {
+		t.Parallel()
+
+		dir := fs.NewDir(t, "preprocessing-replay",
+			fs.WithFile("a.json", string(history)),
+			fs.WithFile("b.json", changed),
+			fs.WithFile("README.md", "Not a history"),
+		)
+
+		var out bytes.Buffer
+		err := replaycmd.NewMain(logr.Discard(), testConfig(), &out).Run([]string{dir.Path()})
+		assert.Error(t, err, "replay: 1 of 2 workflow histories failed")
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Equal(t, len(lines), 2)
+		assert.Equal(t, lines[0], "OK   "+dir.Join("a.json"))
+		assert.Assert(t, strings.HasPrefix(
+			lines[1],
+			"FAIL "+dir.Join("b.json")+": failed in the first workflow task: [TMPRL1100] nondeterministic workflow",
+		), lines[1])
+	})
+
+	t.Run("Reports the last event replayed cleanly", func(t *testing.T) {
+		t.Parallel()
+
+		history, err := os.ReadFile(sinksHistoryPath)
+		assert.NilError(t, err)
+
+		// Replace the activity type scheduled by the fourth workflow task.
+		dir := fs.NewDir(t, "preprocessing-replay", fs.WithFile(
+			"sinks.json",
+			strings.Replace(string(history), `"name": "describe-sip"`, `"name": "other-activity"`, 1),
+		))
+
+		var out bytes.Buffer
+		err = replaycmd.NewMain(logr.Discard(), testConfig(), &out).Run([]string{dir.Join("sinks.json")})
+		assert.Error(t, err, "replay: 1 of 1 workflow histories failed")
+		assert.Assert(t, strings.HasPrefix(
+			out.String(),
+			"FAIL "+dir.Join("sinks.json")+": replayed cleanly up to event ID 26: [TMPRL1100] nondeterministic workflow",
+		), out.String())
+	})
+
+	t.Run("Replays a history of a profile workflow", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := testConfig()
+		cfg.Profiles = []config.Profile{
+			{
+				Name:              "shared",
+				TaskQueue:         "preprocessing-shared",
+				WorkflowName:      "preprocessing",
+				BatchWorkflowName: "preprocessing-batch",
+			},
+			{
+				Name:         "donor",
+				TaskQueue:    "preprocessing-donor",
+				WorkflowName: "preprocessing-donor",
+			},
+		}
+
+		dir := fs.NewDir(t, "preprocessing-replay", fs.WithFile(
+			"donor.json",
+			strings.Replace(string(history), `"name": "preprocessing"`, `"name": "preprocessing-donor"`, 1),
+		))
+
+		var out bytes.Buffer
+		err := replaycmd.NewMain(logr.Discard(), cfg, &out).Run([]string{dir.Join("donor.json")})
+		assert.NilError(t, err)
+		assert.Equal(t, out.String(), "OK   "+dir.Join("donor.json")+"\n")
+	})
+
+	t.Run("Errors when the path is missing", func(t *testing.T) {
+		t.Parallel()
+
+		err := replaycmd.NewMain(logr.Discard(), testConfig(), &bytes.Buffer{}).Run(nil)
+		assert.Error(t, err, "replay: missing workflow history path")
+	})
+
+	t.Run("Errors when the path doesn't exist", func(t *testing.T) {
+		t.Parallel()
+
+		err := replaycmd.NewMain(logr.Discard(), testConfig(), &bytes.Buffer{}).Run([]string{"missing.json"})
+		assert.Error(t, err, "replay: stat missing.json: no such file or directory")
+	})
+}
@@ -2,6 +2,9 @@ package workercmd
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 
 	"github.com/artefactual-sdps/temporal-activities/bagcreate"
 	"github.com/go-logr/logr"
@@ -12,73 +15,260 @@ import (
 	temporalsdk_worker "go.temporal.io/sdk/worker"
 	temporalsdk_workflow "go.temporal.io/sdk/workflow"
 
+	"github.com/artefactual-sdps/preprocessing-base/internal/activities/describesip"
 	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
+	"github.com/artefactual-sdps/preprocessing-base/internal/persistence"
+	"github.com/artefactual-sdps/preprocessing-base/internal/version"
 	"github.com/artefactual-sdps/preprocessing-base/internal/workflow"
 )
 
 const Name = "preprocessing-worker"
 
 type Main struct {
-	logger         logr.Logger
-	cfg            config.Configuration
-	temporalWorker temporalsdk_worker.Worker
-	temporalClient temporalsdk_client.Client
+	logger logr.Logger
+	cfg    config.Configuration
+
+	// current holds the configuration reloaded while the worker is running.
+	current *config.Store
+
+	// profiles hold the current configuration of each profile, in
+	// ProfileConfigs order, they are derived from current.
+	profiles []*config.Store
+
+	// temporalWorkers are the workers of the configuration profiles.
+	temporalWorkers []temporalsdk_worker.Worker
+	temporalClient  temporalsdk_client.Client
+	store           *persistence.Store
 }
 
 func NewMain(logger logr.Logger, cfg config.Configuration) *Main {
-	return &Main{
-		logger: logger,
-		cfg:    cfg,
+	m := &Main{
+		logger:  logger,
+		cfg:     cfg,
+		current: config.NewStore(cfg),
 	}
+	for _, pc := range cfg.ProfileConfigs() {
+		m.profiles = append(m.profiles, config.NewStore(pc))
+	}
+
+	return m
 }
 
 func (m *Main) Run(ctx context.Context) error {
-	c, err := temporalsdk_client.Dial(temporalsdk_client.Options{
-		HostPort:  m.cfg.Temporal.Address,
-		Namespace: m.cfg.Temporal.Namespace,
-		Logger:    temporal.Logger(m.logger.WithName("temporal")),
-	})
+	if err := m.cfg.CheckSharedPath(); err != nil {
+		m.logger.Error(err, "Shared path is not accessible.")
+		return err
+	}
+
+	if err := CheckSteps(m.cfg); err != nil {
+		m.logger.Error(err, "Invalid workflow steps.")
+		return err
+	}
+
+	catalog, err := workflow.LoadCatalog(m.cfg.Messages)
+	if err != nil {
+		m.logger.Error(err, "Unable to load the message catalog.")
+		return err
+	}
+
+	if m.cfg.Database.Enabled {
+		store, err := persistence.Open(ctx, m.cfg.Database)
+		if err != nil {
+			m.logger.Error(err, "Unable to open the database.")
+			return err
+		}
+		m.store = store
+	}
+
+	opts, err := clientOptions(m.cfg.Temporal)
+	if err != nil {
+		m.logger.Error(err, "Invalid Temporal client configuration.")
+		return err
+	}
+	opts.Logger = temporal.Logger(m.logger.WithName("temporal"))
+
+	c, err := temporalsdk_client.Dial(opts)
 	if err != nil {
 		m.logger.Error(err, "Unable to create Temporal client.")
 		return err
 	}
 	m.temporalClient = c
 
-	w := temporalsdk_worker.New(m.temporalClient, m.cfg.Temporal.TaskQueue, temporalsdk_worker.Options{
+	for _, store := range m.profiles {
+		if err := m.startWorker(store, catalog); err != nil {
+			m.logger.Error(
+				err,
+				"Worker failed to start or fatal error during its execution.",
+				"taskQueue", store.Load().Temporal.TaskQueue,
+			)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// startWorker starts a Temporal worker serving the workflows of the profile
+// configuration in store, in its task queue.
+func (m *Main) startWorker(store *config.Store, catalog *eventlog.Catalog) error {
+	cfg := store.Load()
+	w := temporalsdk_worker.New(m.temporalClient, cfg.Temporal.TaskQueue, temporalsdk_worker.Options{
 		EnableSessionWorker:               true,
-		MaxConcurrentSessionExecutionSize: m.cfg.Worker.MaxConcurrentSessions,
+		MaxConcurrentSessionExecutionSize: cfg.Worker.MaxConcurrentSessions,
 		Interceptors: []temporalsdk_interceptor.WorkerInterceptor{
 			temporal.NewLoggerInterceptor(m.logger.WithName("worker")),
 		},
 	})
-	m.temporalWorker = w
+	m.temporalWorkers = append(m.temporalWorkers, w)
 
-	w.RegisterWorkflowWithOptions(
-		workflow.NewPreprocessingWorkflow(m.cfg.SharedPath).Execute,
-		temporalsdk_workflow.RegisterOptions{Name: m.cfg.Temporal.WorkflowName},
-	)
+	RegisterWorkflows(w, store, catalog)
 
 	w.RegisterActivityWithOptions(
-		bagcreate.New(m.cfg.Bagit).Execute,
+		bagCreate,
 		temporalsdk_activity.RegisterOptions{Name: bagcreate.Name},
 	)
+	w.RegisterActivityWithOptions(
+		describesip.New().Execute,
+		temporalsdk_activity.RegisterOptions{Name: describesip.Name},
+	)
+	w.RegisterActivityWithOptions(
+		eventlog.NewAppendActivity().Execute,
+		temporalsdk_activity.RegisterOptions{Name: eventlog.AppendActivityName},
+	)
+	if m.store != nil {
+		w.RegisterActivityWithOptions(
+			persistence.NewSaveRunActivity(m.store).Execute,
+			temporalsdk_activity.RegisterOptions{Name: persistence.SaveRunActivityName},
+		)
+	}
 
-	if err := w.Start(); err != nil {
-		m.logger.Error(err, "Worker failed to start or fatal error during its execution.")
-		return err
+	return w.Start()
+}
+
+// clientOptions returns the Temporal client options of cfg, including the TLS
+// and API key settings.
+func clientOptions(cfg config.Temporal) (temporalsdk_client.Options, error) {
+	opts := temporalsdk_client.Options{
+		HostPort:  cfg.Address,
+		Namespace: cfg.Namespace,
 	}
 
-	return nil
+	tlsConfig, err := cfg.TLS.Load()
+	if err != nil {
+		return opts, fmt.Errorf("Temporal.TLS.%v", err)
+	}
+
+	if cfg.APIKey != "" {
+		opts.Credentials = temporalsdk_client.NewAPIKeyStaticCredentials(cfg.APIKey)
+
+		// API keys must be sent over TLS.
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+	}
+	opts.ConnectionOptions.TLS = tlsConfig
+
+	return opts, nil
+}
+
+// CheckSteps verifies that the workflow steps selected in cfg, its profiles
+// and its overrides are workflow step names.
+func CheckSteps(cfg config.Configuration) error {
+	errs := workflow.CheckSteps("Steps", cfg.Steps)
+	for i, p := range cfg.Profiles {
+		if err := workflow.CheckSteps(fmt.Sprintf("Profiles[%d].Steps", i), p.Steps); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	for i, o := range cfg.Overrides {
+		if err := workflow.CheckSteps(fmt.Sprintf("Overrides[%d].Steps", i), o.Steps); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// Reload applies the reloadable fields of cfg, the configuration read after a
+// change of the configuration file, to the running worker. It logs the
+// changes that need a restart and ignores cfg if err is not nil.
+func (m *Main) Reload(cfg config.Configuration, err error) {
+	if err == nil {
+		err = CheckSteps(cfg)
+	}
+	if err != nil {
+		m.logger.Error(err, "Configuration change ignored, the configuration is not valid.")
+		return
+	}
+
+	for _, field := range m.current.Reload(cfg) {
+		m.logger.Info("Configuration change needs a worker restart to take effect.", "field", field)
+	}
+
+	// The profiles can't be added or removed without a restart, so the
+	// current profile configurations match the profile stores.
+	for i, pc := range m.current.Load().ProfileConfigs() {
+		m.profiles[i].Reload(pc)
+	}
+	m.logger.Info("Configuration reloaded.")
+}
+
+// bagCreate creates a bag with the Bagit configuration recorded in the
+// transfer policy, so configuration changes don't change the bags of running
+// workflows.
+func bagCreate(ctx context.Context, params *workflow.BagCreateParams) (*bagcreate.Result, error) {
+	return bagcreate.New(params.Bagit).Execute(ctx, &params.Params)
+}
+
+// RegisterWorkflows registers the preprocessing workflows of the profile
+// configuration in store with r, it's used by the worker and the workflow
+// replayer. The catalog renders the event messages, the English catalog is
+// used if it's nil. The batch workflow is only registered if
+// Temporal.BatchWorkflowName is set.
+func RegisterWorkflows(r temporalsdk_worker.WorkflowRegistry, store *config.Store, catalog *eventlog.Catalog) {
+	cfg := store.Load()
+	r.RegisterWorkflowWithOptions(
+		workflow.NewPreprocessingWorkflow(store, agents(cfg), catalog).Execute,
+		temporalsdk_workflow.RegisterOptions{Name: cfg.Temporal.WorkflowName},
+	)
+	if cfg.Temporal.BatchWorkflowName == "" {
+		return
+	}
+	r.RegisterWorkflowWithOptions(
+		workflow.NewBatchPreprocessingWorkflow(
+			cfg.Temporal.WorkflowName,
+			cfg.Worker.MaxBatchConcurrency,
+		).Execute,
+		temporalsdk_workflow.RegisterOptions{Name: cfg.Temporal.BatchWorkflowName},
+	)
+}
+
+// agents returns the agents of the preservation events recorded by the
+// worker: the worker software and the organization, if configured.
+func agents(cfg config.Configuration) []eventlog.Agent {
+	agents := []eventlog.Agent{eventlog.SoftwareAgent(Name, version.Long)}
+	if cfg.Organization != "" {
+		agents = append(agents, eventlog.OrganizationAgent(cfg.Organization))
+	}
+
+	return agents
 }
 
 func (m *Main) Close() error {
-	if m.temporalWorker != nil {
-		m.temporalWorker.Stop()
+	for _, w := range m.temporalWorkers {
+		w.Stop()
 	}
 
 	if m.temporalClient != nil {
 		m.temporalClient.Close()
 	}
 
+	if m.store != nil {
+		if err := m.store.Close(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
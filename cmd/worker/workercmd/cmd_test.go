@@ -0,0 +1,177 @@
+package workercmd_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/artefactual-sdps/preprocessing-base/cmd/worker/workercmd"
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+// certFiles returns a directory with a self-signed certificate, "cert.pem",
+// and its private key, "key.pem".
+func certFiles(t *testing.T) *fs.Dir {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "temporal"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NilError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+
+	return fs.NewDir(t, "preprocessing-tls",
+		fs.WithFile("cert.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))),
+		fs.WithFile("key.pem", string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))),
+		fs.WithFile("empty.pem", ""),
+	)
+}
+
+func TestClientOptions(t *testing.T) {
+	t.Parallel()
+
+	dir := certFiles(t)
+
+	type want struct {
+		// tls reports whether the connection uses TLS.
+		tls         bool
+		rootCAs     bool
+		certs       int
+		serverName  string
+		credentials bool
+	}
+
+	for _, tc := range []struct {
+		name    string
+		cfg     config.Temporal
+		want    want
+		wantErr string
+	}{
+		{
+			name: "Connects without TLS",
+			cfg:  config.Temporal{},
+		},
+		{
+			name: "Connects with TLS and the system root CAs",
+			cfg: config.Temporal{
+				TLS: config.TLSConfig{Enabled: true, ServerName: "temporal"},
+			},
+			want: want{tls: true, serverName: "temporal"},
+		},
+		{
+			name: "Connects with TLS and a CA certificate",
+			cfg: config.Temporal{
+				TLS: config.TLSConfig{Enabled: true, CACert: dir.Join("cert.pem")},
+			},
+			want: want{tls: true, rootCAs: true},
+		},
+		{
+			name: "Connects with mutual TLS",
+			cfg: config.Temporal{
+				TLS: config.TLSConfig{
+					Enabled:    true,
+					CACert:     dir.Join("cert.pem"),
+					ClientCert: dir.Join("cert.pem"),
+					ClientKey:  dir.Join("key.pem"),
+				},
+			},
+			want: want{tls: true, rootCAs: true, certs: 1},
+		},
+		{
+			name: "Connects with mutual TLS and the system root CAs",
+			cfg: config.Temporal{
+				TLS: config.TLSConfig{
+					Enabled:    true,
+					ClientCert: dir.Join("cert.pem"),
+					ClientKey:  dir.Join("key.pem"),
+				},
+			},
+			want: want{tls: true, certs: 1},
+		},
+		{
+			name: "Sends the API key over TLS when TLS is not enabled",
+			cfg:  config.Temporal{APIKey: "secret"},
+			want: want{tls: true, credentials: true},
+		},
+		{
+			name: "Sends the API key with the TLS settings",
+			cfg: config.Temporal{
+				APIKey: "secret",
+				TLS: config.TLSConfig{
+					Enabled:    true,
+					CACert:     dir.Join("cert.pem"),
+					ServerName: "temporal",
+				},
+			},
+			want: want{tls: true, rootCAs: true, serverName: "temporal", credentials: true},
+		},
+		{
+			name: "Errors if the CA certificate is not valid",
+			cfg: config.Temporal{
+				APIKey: "secret",
+				TLS:    config.TLSConfig{Enabled: true, CACert: dir.Join("empty.pem")},
+			},
+			wantErr: `Temporal.TLS.CACert: no certificates found in "` + dir.Join("empty.pem") + `"`,
+		},
+		{
+			name: "Errors if the client key is missing",
+			cfg: config.Temporal{
+				TLS: config.TLSConfig{
+					Enabled:    true,
+					ClientCert: dir.Join("cert.pem"),
+					ClientKey:  dir.Join("missing.pem"),
+				},
+			},
+			wantErr: "Temporal.TLS.ClientCert: open " + dir.Join("missing.pem") + ": no such file or directory",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tc.cfg.Address = "temporal:7233"
+			tc.cfg.Namespace = "preprocessing"
+
+			opts, err := workercmd.ClientOptions(tc.cfg)
+			if tc.wantErr != "" {
+				assert.Error(t, err, tc.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, opts.HostPort, "temporal:7233")
+			assert.Equal(t, opts.Namespace, "preprocessing")
+			assert.Equal(t, opts.Credentials != nil, tc.want.credentials)
+
+			got := opts.ConnectionOptions.TLS
+			if !tc.want.tls {
+				assert.Assert(t, got == nil)
+				return
+			}
+			assert.Assert(t, got != nil)
+			assert.Equal(t, got.MinVersion, uint16(tls.VersionTLS12))
+			assert.Equal(t, got.RootCAs != nil, tc.want.rootCAs)
+			assert.Equal(t, len(got.Certificates), tc.want.certs)
+			assert.Equal(t, got.ServerName, tc.want.serverName)
+		})
+	}
+}
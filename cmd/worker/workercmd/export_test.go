@@ -0,0 +1,3 @@
+package workercmd
+
+var ClientOptions = clientOptions
@@ -1,5 +1,9 @@
 ENUMS := \
-	internal/enums/event_outcome_enum.go
+	internal/enums/agent_type_enum.go \
+	internal/enums/detail_severity_enum.go \
+	internal/enums/event_outcome_enum.go \
+	internal/enums/event_type_enum.go \
+	internal/enums/package_type_enum.go
 
 $(ENUMS): GO_ENUM_FLAGS=--marshal --names --ptr --flag --sql --template=$(CURDIR)/hack/make/enums.tmpl
 
@@ -0,0 +1,144 @@
+package describesip
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.artefactual.dev/tools/temporal"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/enums"
+)
+
+const Name = "describe-sip"
+
+// checksumAlgorithms are the BagIt manifest algorithms in order of preference.
+var checksumAlgorithms = []string{"sha512", "sha256", "sha1", "md5"}
+
+type (
+	Params struct {
+		// Path is the path of the SIP to describe.
+		Path string
+	}
+	Result struct {
+		// PackageType is the type of the SIP.
+		PackageType enums.PackageType
+
+		// PayloadFileCount is the number of files in the SIP payload.
+		PayloadFileCount int
+
+		// PayloadByteCount is the total size in bytes of the SIP payload.
+		PayloadByteCount int64
+
+		// ChecksumAlgorithm is the algorithm of the BagIt payload manifest, it's
+		// empty for other package types.
+		ChecksumAlgorithm string
+	}
+	Activity struct{}
+)
+
+func New() *Activity {
+	return &Activity{}
+}
+
+// Execute describes the SIP at params.Path, returning its package type and the
+// size of its payload. The payload of a BagIt bag are the files in its data
+// directory, the payload of a zip file or a directory are all the files they
+// contain.
+func (a *Activity) Execute(ctx context.Context, params *Params) (*Result, error) {
+	logger := temporal.GetLogger(ctx)
+	logger.V(1).Info("Executing describe-sip activity", "Path", params.Path)
+
+	fi, err := os.Stat(params.Path)
+	if err != nil {
+		return nil, fmt.Errorf("describesip: %v", err)
+	}
+
+	var res *Result
+	switch {
+	case !fi.IsDir() && strings.EqualFold(filepath.Ext(params.Path), ".zip"):
+		res, err = describeZip(params.Path)
+	case !fi.IsDir():
+		err = fmt.Errorf("unsupported file type: %s", filepath.Base(params.Path))
+	case isBag(params.Path):
+		res, err = describeBag(params.Path)
+	default:
+		res, err = describeDir(params.Path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("describesip: %v", err)
+	}
+
+	return res, nil
+}
+
+func isBag(path string) bool {
+	_, err := os.Stat(filepath.Join(path, "bagit.txt"))
+	return err == nil
+}
+
+func describeBag(path string) (*Result, error) {
+	res, err := describeDir(filepath.Join(path, "data"))
+	if err != nil {
+		return nil, err
+	}
+	res.PackageType = enums.PackageTypeBag
+
+	for _, alg := range checksumAlgorithms {
+		if _, err := os.Stat(filepath.Join(path, "manifest-"+alg+".txt")); err == nil {
+			res.ChecksumAlgorithm = alg
+			break
+		}
+	}
+
+	return res, nil
+}
+
+func describeDir(path string) (*Result, error) {
+	res := &Result{PackageType: enums.PackageTypeDirectory}
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		res.PayloadFileCount++
+		res.PayloadByteCount += fi.Size()
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func describeZip(path string) (*Result, error) {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	res := &Result{PackageType: enums.PackageTypeZip}
+	for _, f := range r.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		res.PayloadFileCount++
+		res.PayloadByteCount += int64(f.UncompressedSize64) // #nosec G115 -- file sizes fit in int64.
+	}
+
+	return res, nil
+}
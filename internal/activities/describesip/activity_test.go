@@ -0,0 +1,136 @@
+package describesip_test
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	temporalsdk_activity "go.temporal.io/sdk/activity"
+	temporalsdk_testsuite "go.temporal.io/sdk/testsuite"
+	"gotest.tools/v3/assert"
+	tfs "gotest.tools/v3/fs"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/activities/describesip"
+	"github.com/artefactual-sdps/preprocessing-base/internal/enums"
+)
+
+func zipPath(t *testing.T) string {
+	t.Helper()
+
+	p := filepath.Join(t.TempDir(), "transfer.zip")
+	f, err := os.Create(p)
+	assert.NilError(t, err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	_, err = w.Create("dir/")
+	assert.NilError(t, err)
+	for name, content := range map[string]string{
+		"small.txt":       "I am a small file.\n",
+		"dir/another.txt": "I am another file.\n",
+	} {
+		fw, err := w.Create(name)
+		assert.NilError(t, err)
+		_, err = fw.Write([]byte(content))
+		assert.NilError(t, err)
+	}
+	assert.NilError(t, w.Close())
+
+	return p
+}
+
+func TestActivity(t *testing.T) {
+	t.Parallel()
+
+	type test struct {
+		name    string
+		params  describesip.Params
+		want    describesip.Result
+		wantErr string
+	}
+	for _, tt := range []test{
+		{
+			name: "Describes a bag",
+			params: describesip.Params{
+				Path: tfs.NewDir(t, "preprocessing_describe_test",
+					tfs.WithFile("bagit.txt", "BagIt-Version: 0.97\n"),
+					tfs.WithFile("manifest-md5.txt", ""),
+					tfs.WithFile("manifest-sha256.txt", ""),
+					tfs.WithFile("tagmanifest-sha256.txt", ""),
+					tfs.WithDir("data",
+						tfs.WithFile("small.txt", "I am a small file.\n"),
+						tfs.WithDir("dir",
+							tfs.WithFile("another.txt", "I am another file.\n"),
+						),
+					),
+				).Path(),
+			},
+			want: describesip.Result{
+				PackageType:       enums.PackageTypeBag,
+				PayloadFileCount:  2,
+				PayloadByteCount:  38,
+				ChecksumAlgorithm: "sha256",
+			},
+		},
+		{
+			name: "Describes a directory",
+			params: describesip.Params{
+				Path: tfs.NewDir(t, "preprocessing_describe_test",
+					tfs.WithFile("small.txt", "I am a small file.\n"),
+					tfs.WithDir("dir",
+						tfs.WithFile("another.txt", "I am another file.\n"),
+					),
+				).Path(),
+			},
+			want: describesip.Result{
+				PackageType:      enums.PackageTypeDirectory,
+				PayloadFileCount: 2,
+				PayloadByteCount: 38,
+			},
+		},
+		{
+			name:   "Describes a zip file",
+			params: describesip.Params{Path: zipPath(t)},
+			want: describesip.Result{
+				PackageType:      enums.PackageTypeZip,
+				PayloadFileCount: 2,
+				PayloadByteCount: 38,
+			},
+		},
+		{
+			name: "Errors if the file type is not supported",
+			params: describesip.Params{
+				Path: tfs.NewFile(t, "preprocessing_describe_test").Path(),
+			},
+			wantErr: "describesip: unsupported file type: preprocessing_describe_test",
+		},
+		{
+			name:    "Errors if the path doesn't exist",
+			params:  describesip.Params{Path: "/missing/path"},
+			wantErr: "describesip: stat /missing/path: no such file or directory",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ts := &temporalsdk_testsuite.WorkflowTestSuite{}
+			env := ts.NewTestActivityEnvironment()
+			env.RegisterActivityWithOptions(
+				describesip.New().Execute,
+				temporalsdk_activity.RegisterOptions{Name: describesip.Name},
+			)
+
+			enc, err := env.ExecuteActivity(describesip.Name, tt.params)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+
+			var result describesip.Result
+			_ = enc.Get(&result)
+			assert.DeepEqual(t, result, tt.want)
+		})
+	}
+}
@@ -3,13 +3,45 @@ package config
 import (
 	"errors"
 	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/artefactual-sdps/temporal-activities/bagcreate"
 	"github.com/spf13/viper"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
 )
 
+// defaults are the default values of the settings.
+var defaults = map[string]any{
+	"Temporal.Address":             "localhost:7233",
+	"Temporal.Namespace":           "default",
+	"Temporal.BatchWorkflowName":   "preprocessing-batch",
+	"Worker.MaxConcurrentSessions": 1,
+	"Worker.MaxBatchConcurrency":   5,
+	"Worker.ActivityTimeout":       DefaultActivityTimeout,
+	"EventLog.Path":                "preprocessing-events.jsonl",
+	"Database.Driver":              "sqlite",
+	"Messages.Locale":              "en",
+	"Log.MaxSize":                  100,
+}
+
+// DefaultActivityTimeout is the default Worker.ActivityTimeout, also used by
+// the workflows when it's not set.
+const DefaultActivityTimeout = 5 * time.Minute
+
+// LogComponents are the names of the component loggers with a configurable
+// verbosity.
+var LogComponents = []string{"temporal", "worker"}
+
+// Locales are the locales of the built-in message catalogs.
+var Locales = []string{"en", "fr", "de"}
+
 type ConfigurationValidator interface {
 	Validate() error
 }
@@ -31,9 +63,43 @@ type Configuration struct {
 	// Enduro and preservation processing.
 	SharedPath string
 
+	// Organization is the name of the organization responsible for
+	// preprocessing, it's added as an agent to the preservation events
+	// (optional).
+	Organization string
+
+	// Steps lists the names of the preprocessing workflow steps to run, e.g.
+	// "bag-sip", all the steps run if it's empty (default: empty).
+	Steps []string
+
+	// Temporal configures the Temporal server connection and workflows.
 	Temporal Temporal
-	Worker   WorkerConfig
-	Bagit    bagcreate.Config
+
+	// Worker configures the Temporal worker.
+	Worker WorkerConfig
+
+	// Bagit configures the BagIt bags created by the workflow.
+	Bagit bagcreate.Config
+
+	// EventLog configures the preservation event log.
+	EventLog EventLogConfig
+
+	// Database configures the database of the workflow runs.
+	Database DatabaseConfig
+
+	// Messages configures the preservation event messages.
+	Messages MessagesConfig
+
+	// Log configures the log output.
+	Log LogConfig
+
+	// Profiles are additional workflow configurations served by the worker,
+	// each with its own Temporal worker (optional).
+	Profiles []Profile
+
+	// Overrides change the preprocessing policy of the transfers they match,
+	// by relative path or profile workflow param, in order (optional).
+	Overrides []Override
 }
 
 type Temporal struct {
@@ -51,12 +117,136 @@ type Temporal struct {
 	// WorkflowName is the name of the preprocessing Temporal workflow
 	// (required).
 	WorkflowName string
+
+	// BatchWorkflowName is the name of the Temporal workflow that preprocesses
+	// a batch of transfers, starting a child workflow for each of them
+	// (default: "preprocessing-batch").
+	BatchWorkflowName string
+
+	// APIKey authenticates the worker to the Temporal server, e.g. Temporal
+	// Cloud. It implies a TLS connection, verified with the system root CAs
+	// if TLS is not enabled. Keep it secret (optional).
+	APIKey string
+
+	// TLS configures the TLS connection to the Temporal server (optional).
+	TLS TLSConfig
 }
 
 type WorkerConfig struct {
 	// MaxConcurrentSessions limits the number of workflow sessions the
 	// preprocessing worker can handle simultaneously (default: 1).
 	MaxConcurrentSessions int
+
+	// MaxBatchConcurrency limits the number of child workflows that a batch
+	// workflow runs simultaneously, when not set in the workflow params
+	// (default: 5).
+	MaxBatchConcurrency int
+
+	// MinFreeSpace is the minimum free space of the SharedPath filesystem
+	// checked when the worker starts, in bytes or with a unit, e.g. "10GiB"
+	// (default: 0, not checked). It's only supported on Linux and macOS.
+	MinFreeSpace ByteSize
+
+	// ActivityTimeout is the maximum time of each workflow activity, e.g.
+	// "10m" (default: "5m", also used when set to zero).
+	ActivityTimeout time.Duration
+}
+
+type EventLogConfig struct {
+	// Enabled toggles appending the preservation events, as they complete, to
+	// a JSON Lines file in SharedPath (default: false).
+	Enabled bool
+
+	// Path is the SharedPath relative path of the JSON Lines file (default:
+	// "preprocessing-events.jsonl").
+	Path string
+
+	// SignalParent toggles signaling the preservation events to the parent
+	// workflow, e.g. Enduro, as they start and complete (default: false).
+	SignalParent bool
+}
+
+type DatabaseConfig struct {
+	// Enabled toggles recording the workflow runs and their preservation
+	// events in a SQL database (default: false).
+	Enabled bool
+
+	// Driver is the database driver, "sqlite" or "mysql" (default: "sqlite").
+	Driver string
+
+	// DSN is the data source name of the database, the database file path for
+	// SQLite or e.g. "user:password@tcp(mysql:3306)/preprocessing" for MySQL.
+	// It may include credentials, keep it secret (required when enabled).
+	DSN string
+}
+
+type MessagesConfig struct {
+	// Locale is the locale of the preservation event messages, the built-in
+	// catalogs are "en", "fr" and "de" (default: "en").
+	Locale string
+
+	// Catalogs maps locales to the paths of JSON message catalogs, that add to
+	// or override the built-in catalog of the locale (optional).
+	Catalogs map[string]string
+}
+
+// validate verifies that there is a catalog for the locale and that the
+// catalog files can be loaded.
+func (c MessagesConfig) validate() error {
+	var errs error
+
+	locale := c.Locale
+	if locale == "" {
+		locale = "en"
+	}
+	if _, ok := c.Catalogs[locale]; !ok && !slices.Contains(Locales, locale) {
+		errs = errors.Join(errs, fmt.Errorf(
+			"Messages.Locale: no catalog found for locale %q (%s or a locale in Messages.Catalogs)",
+			locale, strings.Join(Locales, ", "),
+		))
+	}
+
+	for _, locale := range slices.Sorted(maps.Keys(c.Catalogs)) {
+		data, err := os.ReadFile(c.Catalogs[locale]) // #nosec G304 -- trusted path.
+		if err == nil {
+			_, err = eventlog.ReadCatalog(locale, data, nil)
+		}
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("Messages.Catalogs.%s: %v", locale, err))
+		}
+	}
+
+	return errs
+}
+
+type LogConfig struct {
+	// Format is the format of the log messages, "json" or "console" (default:
+	// "console" if Debug is set, "json" otherwise).
+	Format string
+
+	// File is the path of the log file, the logs are written to stderr if it's
+	// empty (optional).
+	File string
+
+	// MaxSize is the size in megabytes at which the log file is rotated
+	// (default: 100).
+	MaxSize int
+
+	// MaxBackups is the number of rotated log files to keep, all of them are
+	// kept if it's 0 (default: 0).
+	MaxBackups int
+
+	// MaxAge is the number of days to keep the rotated log files, they are
+	// kept regardless of their age if it's 0 (default: 0).
+	MaxAge int
+
+	// Compress toggles compressing the rotated log files with gzip (default:
+	// false).
+	Compress bool
+
+	// Verbosity maps the component loggers, "temporal" and "worker", to their
+	// verbosity level, overriding the top-level Verbosity (optional).
+	Verbosity map[string]int
 }
 
 func (c Configuration) Validate() error {
@@ -72,6 +262,18 @@ func (c Configuration) Validate() error {
 	if c.Temporal.WorkflowName == "" {
 		errs = errors.Join(errs, errRequired("Temporal.WorkflowName"))
 	}
+	if c.Temporal.BatchWorkflowName == "" {
+		errs = errors.Join(errs, errRequired("Temporal.BatchWorkflowName"))
+	} else if c.Temporal.BatchWorkflowName == c.Temporal.WorkflowName {
+		errs = errors.Join(errs, fmt.Errorf(
+			"Temporal.BatchWorkflowName: %q must be different from Temporal.WorkflowName",
+			c.Temporal.BatchWorkflowName,
+		))
+	}
+
+	if err := c.Temporal.TLS.validate(); err != nil {
+		errs = errors.Join(errs, err)
+	}
 
 	// Verify that MaxConcurrentSessions is >= 1.
 	if c.Worker.MaxConcurrentSessions < 1 {
@@ -81,33 +283,145 @@ func (c Configuration) Validate() error {
 		))
 	}
 
+	// Verify that MaxBatchConcurrency is >= 1.
+	if c.Worker.MaxBatchConcurrency < 1 {
+		errs = errors.Join(errs, fmt.Errorf(
+			"Worker.MaxBatchConcurrency: %d is less than the minimum value (1)",
+			c.Worker.MaxBatchConcurrency,
+		))
+	}
+
+	// Verify that ActivityTimeout is >= 0, zero uses the default.
+	if c.Worker.ActivityTimeout < 0 {
+		errs = errors.Join(errs, fmt.Errorf(
+			"Worker.ActivityTimeout: %s is less than the minimum value (0s)",
+			c.Worker.ActivityTimeout,
+		))
+	}
+
+	// Verify that the event log path is inside SharedPath.
+	if c.EventLog.Enabled && !filepath.IsLocal(c.EventLog.Path) {
+		errs = errors.Join(errs, fmt.Errorf(
+			"EventLog.Path: %q must be a relative path inside SharedPath",
+			c.EventLog.Path,
+		))
+	}
+
+	// Verify the database settings.
+	if c.Database.Enabled {
+		if c.Database.Driver != "sqlite" && c.Database.Driver != "mysql" {
+			errs = errors.Join(errs, fmt.Errorf(
+				"Database.Driver: %q is not a supported driver (sqlite, mysql)",
+				c.Database.Driver,
+			))
+		}
+		if c.Database.DSN == "" {
+			errs = errors.Join(errs, errRequired("Database.DSN"))
+		}
+	}
+
+	// Verify the log settings.
+	if c.Log.Format != "" && c.Log.Format != "json" && c.Log.Format != "console" {
+		errs = errors.Join(errs, fmt.Errorf(
+			"Log.Format: %q is not a supported format (json, console)",
+			c.Log.Format,
+		))
+	}
+	if c.Log.MaxSize < 0 {
+		errs = errors.Join(errs, fmt.Errorf("Log.MaxSize: %d is less than the minimum value (0)", c.Log.MaxSize))
+	}
+	if c.Log.MaxBackups < 0 {
+		errs = errors.Join(errs, fmt.Errorf("Log.MaxBackups: %d is less than the minimum value (0)", c.Log.MaxBackups))
+	}
+	if c.Log.MaxAge < 0 {
+		errs = errors.Join(errs, fmt.Errorf("Log.MaxAge: %d is less than the minimum value (0)", c.Log.MaxAge))
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Log.Verbosity)) {
+		if !slices.Contains(LogComponents, name) {
+			errs = errors.Join(errs, fmt.Errorf(
+				"Log.Verbosity: %q is not a logger (%s)",
+				name, strings.Join(LogComponents, ", "),
+			))
+		}
+	}
+
+	if err := c.Messages.validate(); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
 	if err := c.Bagit.Validate(); err != nil {
 		errs = errors.Join(errs, fmt.Errorf("Bagit.%v", err))
 	}
 
+	if err := c.validateProfiles(); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
+	if err := c.validateOverrides(); err != nil {
+		errs = errors.Join(errs, err)
+	}
+
 	return errs
 }
 
 func Read(config *Configuration, configFile string) (found bool, configFileUsed string, err error) {
-	v := viper.New()
+	v, found, err := load(configFile)
+	if err != nil {
+		return found, "", err
+	}
+
+	// Report the invalid units with the other configuration errors.
+	errs := checkUnits(v)
+
+	err = v.Unmarshal(config, decodeHook())
+	if err != nil {
+		return true, "", fmt.Errorf("failed to unmarshal configuration: %w", err)
+	}
+
+	if err := resolveFiles(reflect.ValueOf(config).Elem(), ""); err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if err := config.Validate(); err != nil {
+		errs = errors.Join(errs, err)
+	}
+	if errs != nil {
+		return true, "", errors.Join(errors.New("invalid configuration:"), errs)
+	}
+
+	return true, v.ConfigFileUsed(), nil
+}
+
+// load returns a viper instance with the configuration file, the environment
+// variables and the defaults loaded.
+func load(configFile string) (v *viper.Viper, found bool, err error) {
+	v = viper.New()
 
 	v.AddConfigPath(".")
 	v.AddConfigPath("$HOME/.config/")
 	v.AddConfigPath("/etc")
 	v.SetConfigName("preprocessing")
-	v.SetEnvPrefix("ENDURO_PREPROCESSING")
+	v.SetEnvPrefix(envPrefix)
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
 
-	// Defaults.
-	v.SetDefault("Worker.MaxConcurrentSessions", 1)
+	// AutomaticEnv only overrides the keys viper knows about, bind the
+	// environment variables of the settings without a default value too.
+	walk(reflect.ValueOf(Configuration{}), "", func(key string, fv reflect.Value) {
+		if fromEnv(fv) {
+			_ = v.BindEnv(key)
+		}
+	})
+
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
 
 	if configFile != "" {
 		// Viper will not return a viper.ConfigFileNotFoundError error when
 		// SetConfigFile() is passed a path to a file that doesn't exist, so we
 		// need to check ourselves.
 		if _, err := os.Stat(configFile); errors.Is(err, os.ErrNotExist) {
-			return false, "", fmt.Errorf("configuration file not found: %s", configFile)
+			return nil, false, fmt.Errorf("configuration file not found: %s", configFile)
 		}
 
 		v.SetConfigFile(configFile)
@@ -116,22 +430,39 @@ func Read(config *Configuration, configFile string) (found bool, configFileUsed
 	if err = v.ReadInConfig(); err != nil {
 		switch err.(type) {
 		case viper.ConfigFileNotFoundError:
-			return false, "", err
+			return nil, false, err
 		default:
-			return true, "", fmt.Errorf("failed to read configuration file: %w", err)
+			return nil, true, fmt.Errorf("failed to read configuration file: %w", err)
 		}
 	}
 
-	err = v.Unmarshal(config)
-	if err != nil {
-		return true, "", fmt.Errorf("failed to unmarshal configuration: %w", err)
-	}
-
-	if err := config.Validate(); err != nil {
-		return true, "", errors.Join(errors.New("invalid configuration:"), err)
+	// The *_FILE environment variables set string settings to a reference to
+	// the file, e.g. a mounted secret, that is resolved after decoding.
+	var errs error
+	walk(reflect.ValueOf(Configuration{}), "", func(key string, fv reflect.Value) {
+		path, ok := os.LookupEnv(EnvName(key) + "_FILE")
+		if !ok {
+			return
+		}
+		if fv.Kind() != reflect.String {
+			errs = errors.Join(errs, fmt.Errorf(
+				"%s: %s_FILE is not supported, only string settings can be read from files", key, EnvName(key),
+			))
+			return
+		}
+		if _, ok := os.LookupEnv(EnvName(key)); ok {
+			errs = errors.Join(errs, fmt.Errorf(
+				"%s: %s and %s_FILE are both set", key, EnvName(key), EnvName(key),
+			))
+			return
+		}
+		v.Set(key, fileScheme+path)
+	})
+	if errs != nil {
+		return nil, true, errors.Join(errors.New("invalid environment:"), errs)
 	}
 
-	return true, v.ConfigFileUsed(), nil
+	return v, true, nil
 }
 
 func errRequired(name string) error {
@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/artefactual-sdps/temporal-activities/bagcreate"
 	"gotest.tools/v3/assert"
@@ -23,6 +24,18 @@ workflowName = "preprocessing"
 maxConcurrentSessions = 1
 [bagit]
 checksumAlgorithm = "md5"
+[eventLog]
+enabled = true
+path = "logs/events.jsonl"
+signalParent = true
+[database]
+enabled = true
+driver = "mysql"
+dsn = "user:password@tcp(mysql:3306)/preprocessing"
+[messages]
+locale = "fr"
+[messages.catalogs]
+fr = "testdata/messages.fr.json"
 `
 
 func TestConfig(t *testing.T) {
@@ -49,17 +62,39 @@ func TestConfig(t *testing.T) {
 				Verbosity:  2,
 				SharedPath: "/home/preprocessing/shared",
 				Temporal: config.Temporal{
-					Address:      "host:port",
-					Namespace:    "default",
-					TaskQueue:    "preprocessing",
-					WorkflowName: "preprocessing",
+					Address:           "host:port",
+					Namespace:         "default",
+					TaskQueue:         "preprocessing",
+					WorkflowName:      "preprocessing",
+					BatchWorkflowName: "preprocessing-batch",
 				},
 				Worker: config.WorkerConfig{
 					MaxConcurrentSessions: 1,
+					MaxBatchConcurrency:   5,
+					ActivityTimeout:       5 * time.Minute,
 				},
 				Bagit: bagcreate.Config{
 					ChecksumAlgorithm: "md5",
 				},
+				EventLog: config.EventLogConfig{
+					Enabled:      true,
+					Path:         "logs/events.jsonl",
+					SignalParent: true,
+				},
+				Database: config.DatabaseConfig{
+					Enabled: true,
+					Driver:  "mysql",
+					DSN:     "user:password@tcp(mysql:3306)/preprocessing",
+				},
+				Messages: config.MessagesConfig{
+					Locale: "fr",
+					Catalogs: map[string]string{
+						"fr": "testdata/messages.fr.json",
+					},
+				},
+				Log: config.LogConfig{
+					MaxSize: 100,
+				},
 			},
 		},
 		{
@@ -85,6 +120,68 @@ maxConcurrentSessions = -1
 			wantFound: true,
 			wantErr: `invalid configuration:
 Worker.MaxConcurrentSessions: -1 is less than the minimum value (1)`,
+		},
+		{
+			name:       "Errors when MaxBatchConcurrency is less than 1",
+			configFile: "preprocessing.toml",
+			toml: `# Config
+sharedPath = "/home/preprocessing/shared"
+[temporal]
+taskQueue = "preprocessing"
+workflowName = "preprocessing"
+[worker]
+maxBatchConcurrency = 0
+`,
+			wantFound: true,
+			wantErr: `invalid configuration:
+Worker.MaxBatchConcurrency: 0 is less than the minimum value (1)`,
+		},
+		{
+			name:       "Errors when the batch workflow name is the workflow name",
+			configFile: "preprocessing.toml",
+			toml: `# Config
+sharedPath = "/home/preprocessing/shared"
+[temporal]
+taskQueue = "preprocessing"
+workflowName = "preprocessing"
+batchWorkflowName = "preprocessing"
+`,
+			wantFound: true,
+			wantErr: `invalid configuration:
+Temporal.BatchWorkflowName: "preprocessing" must be different from Temporal.WorkflowName`,
+		},
+		{
+			name:       "Errors when the event log path is outside SharedPath",
+			configFile: "preprocessing.toml",
+			toml: `# Config
+sharedPath = "/home/preprocessing/shared"
+[temporal]
+taskQueue = "preprocessing"
+workflowName = "preprocessing"
+[eventLog]
+enabled = true
+path = "../events.jsonl"
+`,
+			wantFound: true,
+			wantErr: `invalid configuration:
+EventLog.Path: "../events.jsonl" must be a relative path inside SharedPath`,
+		},
+		{
+			name:       "Errors when the database settings are not valid",
+			configFile: "preprocessing.toml",
+			toml: `# Config
+sharedPath = "/home/preprocessing/shared"
+[temporal]
+taskQueue = "preprocessing"
+workflowName = "preprocessing"
+[database]
+enabled = true
+driver = "postgres"
+`,
+			wantFound: true,
+			wantErr: `invalid configuration:
+Database.Driver: "postgres" is not a supported driver (sqlite, mysql)
+Database.DSN: missing required value`,
 		},
 		{
 			name:       "Errors when bagit checksumAlgorithm is invalid",
@@ -100,6 +197,99 @@ checksumAlgorithm = "unknown"
 			wantFound: true,
 			wantErr: `invalid configuration:
 Bagit.ChecksumAlgorithm: invalid value "unknown", must be one of (md5, sha1, sha256, sha512)`,
+		},
+		{
+			name:       "Errors when the log settings are not valid",
+			configFile: "preprocessing.toml",
+			toml: `# Config
+sharedPath = "/home/preprocessing/shared"
+[temporal]
+taskQueue = "preprocessing"
+workflowName = "preprocessing"
+[log]
+format = "text"
+maxSize = -1
+maxBackups = -1
+maxAge = -1
+[log.verbosity]
+temporal = 1
+activities = 2
+`,
+			wantFound: true,
+			wantErr: `invalid configuration:
+Log.Format: "text" is not a supported format (json, console)
+Log.MaxSize: -1 is less than the minimum value (0)
+Log.MaxBackups: -1 is less than the minimum value (0)
+Log.MaxAge: -1 is less than the minimum value (0)
+Log.Verbosity: "activities" is not a logger (temporal, worker)`,
+		},
+		{
+			name:       "Errors when the message catalogs can't be loaded",
+			configFile: "preprocessing.toml",
+			toml: `# Config
+sharedPath = "/home/preprocessing/shared"
+[temporal]
+taskQueue = "preprocessing"
+workflowName = "preprocessing"
+[messages]
+locale = "pt"
+[messages.catalogs]
+es = "testdata/missing.json"
+it = "testdata/messages.invalid.json"
+`,
+			wantFound: true,
+			wantErr: `invalid configuration:
+Messages.Locale: no catalog found for locale "pt" (en, fr, de or a locale in Messages.Catalogs)
+Messages.Catalogs.es: open testdata/missing.json: no such file or directory
+Messages.Catalogs.it: eventlog: catalog "it": json: cannot unmarshal array into Go struct field .bag-sip.succeeded of type string`,
+		},
+		{
+			name:       "Errors when the profiles are not valid",
+			configFile: "preprocessing.toml",
+			toml: `# Config
+sharedPath = "/home/preprocessing/shared"
+[temporal]
+taskQueue = "preprocessing"
+workflowName = "preprocessing"
+[[profiles]]
+name = "donor-a"
+taskQueue = "preprocessing"
+workflowName = "donor-a"
+batchWorkflowName = "donor-a"
+[[profiles]]
+name = "donor-a"
+[profiles.bagit]
+checksumAlgorithm = "unknown"
+`,
+			wantFound: true,
+			wantErr: `invalid configuration:
+Profiles[0].TaskQueue: "preprocessing" is used by another profile
+Profiles[0].BatchWorkflowName: "donor-a" must be different from Profiles[0].WorkflowName
+Profiles[1].Name: "donor-a" is used by another profile
+Profiles[1].TaskQueue: missing required value
+Profiles[1].WorkflowName: missing required value
+Profiles[1].Bagit.ChecksumAlgorithm: invalid value "unknown", must be one of (md5, sha1, sha256, sha512)`,
+		},
+		{
+			name:       "Errors when the overrides are not valid",
+			configFile: "preprocessing.toml",
+			toml: `# Config
+sharedPath = "/home/preprocessing/shared"
+[temporal]
+taskQueue = "preprocessing"
+workflowName = "preprocessing"
+[[overrides]]
+steps = ["bag-sip"]
+[[overrides]]
+relativePath = "donor-[a/*"
+[overrides.bagit]
+checksumAlgorithm = "unknown"
+`,
+			wantFound: true,
+			wantErr: `invalid configuration:
+Overrides[0]: missing RelativePath or Profile value
+Overrides[1].RelativePath: "donor-[a/*" is not a valid glob pattern
+Overrides[1].Bagit.ChecksumAlgorithm: invalid value "unknown", must be one of (md5, sha1, sha256, sha512)`,
 		},
 		{
 			name:       "Errors when TOML is invalid",
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const envPrefix = "ENDURO_PREPROCESSING"
+
+// secrets lists the settings that may include credentials, their values are
+// redacted by Describe.
+var secrets = map[string]bool{
+	"Temporal.APIKey": true,
+	"Database.DSN":    true,
+}
+
+// Redacted replaces the value of the secret settings set by Describe.
+const Redacted = "********"
+
+// Source is where the value of a setting comes from.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+)
+
+// Setting is the effective value of a configuration setting.
+type Setting struct {
+	// Key is the path of the Configuration field, e.g. "Temporal.Address".
+	Key    string
+	Value  any
+	Source Source
+}
+
+// Describe reads the configuration like Read, without validating it, and
+// returns its settings in the Configuration fields order. The values of the
+// secret settings are replaced with Redacted.
+func Describe(configFile string) (settings []Setting, configFileUsed string, err error) {
+	v, _, err := load(configFile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := checkUnits(v); err != nil {
+		return nil, "", errors.Join(errors.New("invalid configuration:"), err)
+	}
+
+	var cfg Configuration
+	if err := v.Unmarshal(&cfg, decodeHook()); err != nil {
+		return nil, "", fmt.Errorf("failed to unmarshal configuration: %w", err)
+	}
+	if err := resolveFiles(reflect.ValueOf(&cfg).Elem(), ""); err != nil {
+		return nil, "", err
+	}
+
+	walk(reflect.ValueOf(cfg), "", func(key string, fv reflect.Value) {
+		s := Setting{Key: key, Value: fv.Interface(), Source: SourceDefault}
+		if envSet(key) && fromEnv(fv) {
+			s.Source = SourceEnv
+		} else if v.InConfig(strings.ToLower(key)) {
+			s.Source = SourceFile
+		}
+		if secrets[key] && !fv.IsZero() {
+			s.Value = Redacted
+		}
+		settings = append(settings, s)
+	})
+
+	return settings, v.ConfigFileUsed(), nil
+}
+
+// fromEnv reports whether the setting of value v can be set with an
+// environment variable, maps and lists of tables can only be set in the
+// configuration file.
+func fromEnv(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map:
+		return false
+	case reflect.Slice:
+		return v.Type().Elem().Kind() != reflect.Struct
+	default:
+		return true
+	}
+}
+
+// envSet reports whether the environment variable of the setting key, or
+// its *_FILE variant, is set.
+func envSet(key string) bool {
+	_, ok := os.LookupEnv(EnvName(key))
+	if !ok {
+		_, ok = os.LookupEnv(EnvName(key) + "_FILE")
+	}
+
+	return ok
+}
+
+// EnvName returns the name of the environment variable that overrides the
+// setting key, e.g. "ENDURO_PREPROCESSING_TEMPORAL_ADDRESS".
+func EnvName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// TOMLKey returns the key of a setting in the TOML configuration file, e.g.
+// "temporal.taskQueue" for "Temporal.TaskQueue".
+func TOMLKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, p := range parts {
+		parts[i] = lowerCamel(p)
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// TOMLValue formats the value of a setting as a TOML value.
+func TOMLValue(v any) string {
+	// Settings with units, e.g. config.ByteSize, are printed with their unit.
+	if s, ok := v.(fmt.Stringer); ok {
+		return strconv.Quote(s.String())
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return strconv.Quote(rv.String())
+	case reflect.Map:
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = fmt.Sprintf("%s = %s", strconv.Quote(k), TOMLValue(rv.MapIndex(reflect.ValueOf(k)).Interface()))
+		}
+		return "{" + strings.Join(pairs, ", ") + "}"
+	case reflect.Slice:
+		values := make([]string, rv.Len())
+		for i := range rv.Len() {
+			values[i] = TOMLValue(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(values, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// lowerCamel lowercases the leading upper case letters of name, keeping the
+// first letter of the next word, e.g. "DSN" is "dsn" and "APIKey" is
+// "apiKey".
+func lowerCamel(name string) string {
+	r := []rune(name)
+
+	n := 0
+	for n < len(r) && unicode.IsUpper(r[n]) {
+		n++
+	}
+	if n > 1 && n < len(r) {
+		n--
+	}
+	for i := range n {
+		r[i] = unicode.ToLower(r[i])
+	}
+
+	return string(r)
+}
+
+// walk calls fn with the path and value of each field of v that isn't a
+// struct, descending into the struct fields.
+func walk(v reflect.Value, path string, fn func(key string, v reflect.Value)) {
+	if v.Kind() != reflect.Struct {
+		fn(path, v)
+		return
+	}
+
+	for i := range v.NumField() {
+		f := v.Type().Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		key := f.Name
+		if path != "" {
+			key = path + "." + key
+		}
+		walk(v.Field(i), key, fn)
+	}
+}
@@ -0,0 +1,144 @@
+package config_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+func TestDescribe(t *testing.T) {
+	t.Setenv("ENDURO_PREPROCESSING_TEMPORAL_ADDRESS", "temporal:7233")
+	t.Setenv("ENDURO_PREPROCESSING_DATABASE_DSN", "user:password@tcp(mysql:3306)/preprocessing")
+
+	tmpDir := fs.NewDir(t, "preprocessing-test", fs.WithFile("preprocessing.toml", testConfig))
+
+	settings, configFileUsed, err := config.Describe(tmpDir.Join("preprocessing.toml"))
+	assert.NilError(t, err)
+	assert.Equal(t, configFileUsed, tmpDir.Join("preprocessing.toml"))
+	assert.DeepEqual(t, settings, []config.Setting{
+		{Key: "Debug", Value: true, Source: config.SourceFile},
+		{Key: "Verbosity", Value: 2, Source: config.SourceFile},
+		{Key: "SharedPath", Value: "/home/preprocessing/shared", Source: config.SourceFile},
+		{Key: "Organization", Value: "", Source: config.SourceDefault},
+		{Key: "Steps", Value: []string(nil), Source: config.SourceDefault},
+		{Key: "Temporal.Address", Value: "temporal:7233", Source: config.SourceEnv},
+		{Key: "Temporal.Namespace", Value: "default", Source: config.SourceFile},
+		{Key: "Temporal.TaskQueue", Value: "preprocessing", Source: config.SourceFile},
+		{Key: "Temporal.WorkflowName", Value: "preprocessing", Source: config.SourceFile},
+		{Key: "Temporal.BatchWorkflowName", Value: "preprocessing-batch", Source: config.SourceDefault},
+		{Key: "Temporal.APIKey", Value: "", Source: config.SourceDefault},
+		{Key: "Temporal.TLS.Enabled", Value: false, Source: config.SourceDefault},
+		{Key: "Temporal.TLS.CACert", Value: "", Source: config.SourceDefault},
+		{Key: "Temporal.TLS.ClientCert", Value: "", Source: config.SourceDefault},
+		{Key: "Temporal.TLS.ClientKey", Value: "", Source: config.SourceDefault},
+		{Key: "Temporal.TLS.ServerName", Value: "", Source: config.SourceDefault},
+		{Key: "Worker.MaxConcurrentSessions", Value: 1, Source: config.SourceFile},
+		{Key: "Worker.MaxBatchConcurrency", Value: 5, Source: config.SourceDefault},
+		{Key: "Worker.MinFreeSpace", Value: config.ByteSize(0), Source: config.SourceDefault},
+		{Key: "Worker.ActivityTimeout", Value: 5 * time.Minute, Source: config.SourceDefault},
+		{Key: "Bagit.ChecksumAlgorithm", Value: "md5", Source: config.SourceFile},
+		{Key: "EventLog.Enabled", Value: true, Source: config.SourceFile},
+		{Key: "EventLog.Path", Value: "logs/events.jsonl", Source: config.SourceFile},
+		{Key: "EventLog.SignalParent", Value: true, Source: config.SourceFile},
+		{Key: "Database.Enabled", Value: true, Source: config.SourceFile},
+		{Key: "Database.Driver", Value: "mysql", Source: config.SourceFile},
+		{Key: "Database.DSN", Value: config.Redacted, Source: config.SourceEnv},
+		{Key: "Messages.Locale", Value: "fr", Source: config.SourceFile},
+		{
+			Key:    "Messages.Catalogs",
+			Value:  map[string]string{"fr": "testdata/messages.fr.json"},
+			Source: config.SourceFile,
+		},
+		{Key: "Log.Format", Value: "", Source: config.SourceDefault},
+		{Key: "Log.File", Value: "", Source: config.SourceDefault},
+		{Key: "Log.MaxSize", Value: 100, Source: config.SourceDefault},
+		{Key: "Log.MaxBackups", Value: 0, Source: config.SourceDefault},
+		{Key: "Log.MaxAge", Value: 0, Source: config.SourceDefault},
+		{Key: "Log.Compress", Value: false, Source: config.SourceDefault},
+		{Key: "Log.Verbosity", Value: map[string]int(nil), Source: config.SourceDefault},
+		{Key: "Profiles", Value: []config.Profile(nil), Source: config.SourceDefault},
+		{Key: "Overrides", Value: []config.Override(nil), Source: config.SourceDefault},
+	})
+}
+
+func TestEnvOverridesSettingsWithoutDefault(t *testing.T) {
+	t.Setenv("ENDURO_PREPROCESSING_ORGANIZATION", "Artefactual Systems")
+
+	tmpDir := fs.NewDir(t, "preprocessing-test", fs.WithFile("preprocessing.toml", testConfig))
+
+	var c config.Configuration
+	_, _, err := config.Read(&c, tmpDir.Join("preprocessing.toml"))
+	assert.NilError(t, err)
+	assert.Equal(t, c.Organization, "Artefactual Systems")
+}
+
+func TestDefaultsTemplate(t *testing.T) {
+	t.Parallel()
+
+	// Uncomment the settings to check the default values of the template.
+	toml := regexp.MustCompile(`(?m)^# (\w+ = )`).ReplaceAllString(config.DefaultsTemplate, "$1")
+	tmpDir := fs.NewDir(t, "preprocessing-test", fs.WithFile("preprocessing.toml", toml))
+
+	var c config.Configuration
+	_, _, err := config.Read(&c, tmpDir.Join("preprocessing.toml"))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, c, config.Configuration{
+		SharedPath: "/home/preprocessing/shared",
+		Steps:      []string{},
+		Temporal: config.Temporal{
+			Address:           "localhost:7233",
+			Namespace:         "default",
+			TaskQueue:         "preprocessing",
+			WorkflowName:      "preprocessing",
+			BatchWorkflowName: "preprocessing-batch",
+		},
+		Worker: config.WorkerConfig{
+			MaxConcurrentSessions: 1,
+			MaxBatchConcurrency:   5,
+			ActivityTimeout:       5 * time.Minute,
+		},
+		EventLog: config.EventLogConfig{
+			Path: "preprocessing-events.jsonl",
+		},
+		Database: config.DatabaseConfig{
+			Driver: "sqlite",
+		},
+		Messages: config.MessagesConfig{
+			Locale: "en",
+		},
+		Log: config.LogConfig{
+			MaxSize: 100,
+		},
+	})
+
+	// Check that every setting is in the template.
+	settings, _, err := config.Describe(tmpDir.Join("preprocessing.toml"))
+	assert.NilError(t, err)
+	for _, s := range settings {
+		switch s.Key {
+		case "Messages.Catalogs", "Log.Verbosity", "Profiles", "Overrides":
+			continue
+		}
+		assert.Equal(t, s.Source, config.SourceFile, "setting %s is not in the template", s.Key)
+	}
+}
+
+func TestTOMLKey(t *testing.T) {
+	t.Parallel()
+
+	for key, want := range map[string]string{
+		"Debug":                   "debug",
+		"SharedPath":              "sharedPath",
+		"Temporal.TaskQueue":      "temporal.taskQueue",
+		"Database.DSN":            "database.dsn",
+		"Bagit.ChecksumAlgorithm": "bagit.checksumAlgorithm",
+		"Temporal.APIKey":         "temporal.apiKey",
+	} {
+		assert.Equal(t, config.TOMLKey(key), want)
+	}
+}
@@ -0,0 +1,69 @@
+// Code generated by gen_docs.go; DO NOT EDIT.
+
+package config
+
+// fieldDocs are the doc comments of the configuration struct fields, by
+// package, type and field name, e.g. "config.Temporal.Address".
+var fieldDocs = map[string]string{
+	"bagcreate.Config.ChecksumAlgorithm":        "ChecksumAlgorithm specifies the hashing algorithm used to generate file checksums. Valid values are \"md5\", \"sha1\", \"sha256\", \"sha512\" (default)",
+	"config.Configuration.Bagit":                "Bagit configures the BagIt bags created by the workflow.",
+	"config.Configuration.Database":             "Database configures the database of the workflow runs.",
+	"config.Configuration.Debug":                "Debug toggles human readable logs or JSON logs (default).",
+	"config.Configuration.EventLog":             "EventLog configures the preservation event log.",
+	"config.Configuration.Log":                  "Log configures the log output.",
+	"config.Configuration.Messages":             "Messages configures the preservation event messages.",
+	"config.Configuration.Organization":         "Organization is the name of the organization responsible for preprocessing, it's added as an agent to the preservation events (optional).",
+	"config.Configuration.Overrides":            "Overrides change the preprocessing policy of the transfers they match, by relative path or profile workflow param, in order (optional).",
+	"config.Configuration.Profiles":             "Profiles are additional workflow configurations served by the worker, each with its own Temporal worker (optional).",
+	"config.Configuration.SharedPath":           "SharedPath is a file path that both Preprocessing and Enduro can access (required).\n\nEnduro will deposit transfers in SharedPath for preprocessing. Preprocessing must write transfer updates to SharedPath for retrieval by Enduro and preservation processing.",
+	"config.Configuration.Steps":                "Steps lists the names of the preprocessing workflow steps to run, e.g. \"bag-sip\", all the steps run if it's empty (default: empty).",
+	"config.Configuration.Temporal":             "Temporal configures the Temporal server connection and workflows.",
+	"config.Configuration.Verbosity":            "Verbosity sets the verbosity level of log messages, with 0 (default) logging only critical messages and each higher number increasing the number of messages logged.",
+	"config.Configuration.Worker":               "Worker configures the Temporal worker.",
+	"config.DatabaseConfig.DSN":                 "DSN is the data source name of the database, the database file path for SQLite or e.g. \"user:password@tcp(mysql:3306)/preprocessing\" for MySQL. It may include credentials, keep it secret (required when enabled).",
+	"config.DatabaseConfig.Driver":              "Driver is the database driver, \"sqlite\" or \"mysql\" (default: \"sqlite\").",
+	"config.DatabaseConfig.Enabled":             "Enabled toggles recording the workflow runs and their preservation events in a SQL database (default: false).",
+	"config.EventLogConfig.Enabled":             "Enabled toggles appending the preservation events, as they complete, to a JSON Lines file in SharedPath (default: false).",
+	"config.EventLogConfig.Path":                "Path is the SharedPath relative path of the JSON Lines file (default: \"preprocessing-events.jsonl\").",
+	"config.EventLogConfig.SignalParent":        "SignalParent toggles signaling the preservation events to the parent workflow, e.g. Enduro, as they start and complete (default: false).",
+	"config.LogConfig.Compress":                 "Compress toggles compressing the rotated log files with gzip (default: false).",
+	"config.LogConfig.File":                     "File is the path of the log file, the logs are written to stderr if it's empty (optional).",
+	"config.LogConfig.Format":                   "Format is the format of the log messages, \"json\" or \"console\" (default: \"console\" if Debug is set, \"json\" otherwise).",
+	"config.LogConfig.MaxAge":                   "MaxAge is the number of days to keep the rotated log files, they are kept regardless of their age if it's 0 (default: 0).",
+	"config.LogConfig.MaxBackups":               "MaxBackups is the number of rotated log files to keep, all of them are kept if it's 0 (default: 0).",
+	"config.LogConfig.MaxSize":                  "MaxSize is the size in megabytes at which the log file is rotated (default: 100).",
+	"config.LogConfig.Verbosity":                "Verbosity maps the component loggers, \"temporal\" and \"worker\", to their verbosity level, overriding the top-level Verbosity (optional).",
+	"config.MessagesConfig.Catalogs":            "Catalogs maps locales to the paths of JSON message catalogs, that add to or override the built-in catalog of the locale (optional).",
+	"config.MessagesConfig.Locale":              "Locale is the locale of the preservation event messages, the built-in catalogs are \"en\", \"fr\" and \"de\" (default: \"en\").",
+	"config.Override.Bagit":                     "Bagit is the BagIt bag configuration of the matched transfers (optional).",
+	"config.Override.Profile":                   "Profile matches the Profile workflow param of the transfers (required if RelativePath is empty).",
+	"config.Override.RelativePath":              "RelativePath is a glob pattern, e.g. \"donor-a/*\", matching the SharedPath relative path of the transfers (required if Profile is empty).",
+	"config.Override.Steps":                     "Steps lists the names of the preprocessing workflow steps to run, replacing the configuration steps (optional).",
+	"config.Policy.Bagit":                       "Bagit is the BagIt bag configuration of the transfer.",
+	"config.Policy.Database":                    "Database reports whether the worker that started the transfer workflow saves the workflow run in the database.",
+	"config.Policy.EventLog":                    "EventLog is the event log configuration of the worker that started the transfer workflow.",
+	"config.Policy.Steps":                       "Steps lists the names of the preprocessing workflow steps to run, all the steps run if it's empty.",
+	"config.Profile.Bagit":                      "Bagit is the BagIt bag configuration of the profile (default: the top-level Bagit configuration).",
+	"config.Profile.BatchWorkflowName":          "BatchWorkflowName is the name of the batch workflow, the batch workflow is not registered if empty (optional).",
+	"config.Profile.Name":                       "Name identifies the profile in the logs (required).",
+	"config.Profile.Steps":                      "Steps lists the names of the preprocessing workflow steps to run, all the steps run if it's empty (default: empty).",
+	"config.Profile.TaskQueue":                  "TaskQueue is the Temporal task queue of the profile worker, it must be unique (required).",
+	"config.Profile.WorkflowName":               "WorkflowName is the name of the preprocessing workflow (required).",
+	"config.Setting.Key":                        "Key is the path of the Configuration field, e.g. \"Temporal.Address\".",
+	"config.TLSConfig.CACert":                   "CACert is the path of the PEM encoded CA certificates that verify the server certificate, the system root CAs are used if empty (optional).",
+	"config.TLSConfig.ClientCert":               "ClientCert is the path of the PEM encoded client certificate for mutual TLS, it requires ClientKey (optional).",
+	"config.TLSConfig.ClientKey":                "ClientKey is the path of the PEM encoded private key of ClientCert (optional).",
+	"config.TLSConfig.Enabled":                  "Enabled toggles the TLS connection to the Temporal server (default: false).",
+	"config.TLSConfig.ServerName":               "ServerName is the name used to verify the server certificate, when it doesn't match the host of Temporal.Address (optional).",
+	"config.Temporal.APIKey":                    "APIKey authenticates the worker to the Temporal server, e.g. Temporal Cloud. It implies a TLS connection, verified with the system root CAs if TLS is not enabled. Keep it secret (optional).",
+	"config.Temporal.Address":                   "Address is the Temporal server host and port (default: \"localhost:7233\").",
+	"config.Temporal.BatchWorkflowName":         "BatchWorkflowName is the name of the Temporal workflow that preprocesses a batch of transfers, starting a child workflow for each of them (default: \"preprocessing-batch\").",
+	"config.Temporal.Namespace":                 "Namespace is the Temporal namespace the preprocessing worker should run in (default: \"default\").",
+	"config.Temporal.TLS":                       "TLS configures the TLS connection to the Temporal server (optional).",
+	"config.Temporal.TaskQueue":                 "TaskQueue is the Temporal task queue from which the preprocessing worker will pull tasks (required).",
+	"config.Temporal.WorkflowName":              "WorkflowName is the name of the preprocessing Temporal workflow (required).",
+	"config.WorkerConfig.ActivityTimeout":       "ActivityTimeout is the maximum time of each workflow activity, e.g. \"10m\" (default: \"5m\", also used when set to zero).",
+	"config.WorkerConfig.MaxBatchConcurrency":   "MaxBatchConcurrency limits the number of child workflows that a batch workflow runs simultaneously, when not set in the workflow params (default: 5).",
+	"config.WorkerConfig.MaxConcurrentSessions": "MaxConcurrentSessions limits the number of workflow sessions the preprocessing worker can handle simultaneously (default: 1).",
+	"config.WorkerConfig.MinFreeSpace":          "MinFreeSpace is the minimum free space of the SharedPath filesystem checked when the worker starts, in bytes or with a unit, e.g. \"10GiB\" (default: 0, not checked). It's only supported on Linux and macOS.",
+}
@@ -0,0 +1,13 @@
+package config
+
+import "testing"
+
+var ErrFreeSpaceUnsupported = errFreeSpaceUnsupported
+
+// SetFreeSpace replaces the free space check of CheckSharedPath with fn until
+// the test finishes. Tests using it can't run in parallel.
+func SetFreeSpace(t *testing.T, fn func(path string) (uint64, error)) {
+	prev := freeSpace
+	freeSpace = fn
+	t.Cleanup(func() { freeSpace = prev })
+}
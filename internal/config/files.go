@@ -0,0 +1,59 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// fileScheme prefixes the setting values read from a file, e.g.
+// "file:///run/secrets/temporal-api-key".
+const fileScheme = "file://"
+
+// resolveFiles replaces the string settings of v, and of the structs it
+// contains, that reference a file with the content of the file, without the
+// trailing newline.
+func resolveFiles(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Struct:
+		var errs error
+		for i := range v.NumField() {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+
+			key := f.Name
+			if path != "" {
+				key = path + "." + key
+			}
+			if err := resolveFiles(v.Field(i), key); err != nil {
+				errs = errors.Join(errs, err)
+			}
+		}
+		return errs
+	case reflect.Slice:
+		var errs error
+		for i := range v.Len() {
+			if err := resolveFiles(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				errs = errors.Join(errs, err)
+			}
+		}
+		return errs
+	case reflect.String:
+		name, ok := strings.CutPrefix(v.String(), fileScheme)
+		if !ok {
+			return nil
+		}
+
+		b, err := os.ReadFile(name) // #nosec G304 -- path set in the configuration.
+		if err != nil {
+			return fmt.Errorf("%s: read file: %v", path, err)
+		}
+		v.SetString(strings.TrimRight(string(b), "\r\n"))
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package config_test
+
+import (
+	"fmt"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+const fileRefConfig = `sharedPath = "/home/preprocessing/shared"
+[temporal]
+taskQueue = "preprocessing"
+workflowName = "preprocessing"
+[database]
+enabled = true
+driver = "mysql"
+dsn = "file://%s"
+`
+
+func TestFileReferences(t *testing.T) {
+	t.Run("Reads the settings from files", func(t *testing.T) {
+		secrets := fs.NewDir(t, "preprocessing-secrets",
+			fs.WithFile("dsn", "user:password@tcp(mysql:3306)/preprocessing\n"),
+			fs.WithFile("api-key", "secret\r\n"),
+		)
+		tmpDir := fs.NewDir(t, "preprocessing-test",
+			fs.WithFile("preprocessing.toml", fmt.Sprintf(fileRefConfig, secrets.Join("dsn"))),
+		)
+		t.Setenv("ENDURO_PREPROCESSING_TEMPORAL_APIKEY_FILE", secrets.Join("api-key"))
+
+		var c config.Configuration
+		_, _, err := config.Read(&c, tmpDir.Join("preprocessing.toml"))
+		assert.NilError(t, err)
+		assert.Equal(t, c.Database.DSN, "user:password@tcp(mysql:3306)/preprocessing")
+		assert.Equal(t, c.Temporal.APIKey, "secret")
+
+		settings, _, err := config.Describe(tmpDir.Join("preprocessing.toml"))
+		assert.NilError(t, err)
+		for _, s := range settings {
+			if s.Key == "Temporal.APIKey" {
+				assert.DeepEqual(t, s, config.Setting{
+					Key:    "Temporal.APIKey",
+					Value:  config.Redacted,
+					Source: config.SourceEnv,
+				})
+			}
+		}
+	})
+
+	t.Run("Errors when a file can't be read", func(t *testing.T) {
+		secrets := fs.NewDir(t, "preprocessing-secrets")
+		tmpDir := fs.NewDir(t, "preprocessing-test",
+			fs.WithFile("preprocessing.toml", fmt.Sprintf(fileRefConfig, secrets.Join("missing"))),
+		)
+
+		var c config.Configuration
+		_, _, err := config.Read(&c, tmpDir.Join("preprocessing.toml"))
+		assert.Error(t, err, `invalid configuration:
+Database.DSN: read file: open `+secrets.Join("missing")+`: no such file or directory`)
+	})
+
+	t.Run("Errors when a setting and its file are both set", func(t *testing.T) {
+		tmpDir := fs.NewDir(t, "preprocessing-test", fs.WithFile("preprocessing.toml", testConfig))
+		t.Setenv("ENDURO_PREPROCESSING_TEMPORAL_APIKEY", "secret")
+		t.Setenv("ENDURO_PREPROCESSING_TEMPORAL_APIKEY_FILE", "/run/secrets/api-key")
+
+		var c config.Configuration
+		_, _, err := config.Read(&c, tmpDir.Join("preprocessing.toml"))
+		assert.Error(t, err, `invalid environment:
+Temporal.APIKey: ENDURO_PREPROCESSING_TEMPORAL_APIKEY and ENDURO_PREPROCESSING_TEMPORAL_APIKEY_FILE are both set`)
+	})
+
+	t.Run("Errors when a file is set for a setting that isn't a string", func(t *testing.T) {
+		tmpDir := fs.NewDir(t, "preprocessing-test", fs.WithFile("preprocessing.toml", testConfig))
+		t.Setenv("ENDURO_PREPROCESSING_VERBOSITY_FILE", "/run/secrets/verbosity")
+
+		var c config.Configuration
+		_, _, err := config.Read(&c, tmpDir.Join("preprocessing.toml"))
+		assert.Error(t, err, `invalid environment:
+Verbosity: ENDURO_PREPROCESSING_VERBOSITY_FILE is not supported, only string settings can be read from files`)
+	})
+}
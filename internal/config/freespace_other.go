@@ -0,0 +1,7 @@
+//go:build !linux && !darwin
+
+package config
+
+func filesystemFreeSpace(path string) (uint64, error) {
+	return 0, errFreeSpaceUnsupported
+}
@@ -0,0 +1,16 @@
+//go:build linux || darwin
+
+package config
+
+import "golang.org/x/sys/unix"
+
+// filesystemFreeSpace returns the bytes available to unprivileged users in the
+// filesystem of path.
+func filesystemFreeSpace(path string) (uint64, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+
+	return st.Bavail * uint64(st.Bsize), nil // #nosec G115 -- Bsize is positive.
+}
@@ -0,0 +1,134 @@
+//go:build ignore
+
+// gen_docs extracts the doc comments of the configuration struct fields, from
+// this package and the packages of the nested configurations, to
+// docs_gen.go. Run it with go generate.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// packages maps the import paths of the packages with configuration structs
+// used by Configuration, besides this package, to the names of the structs.
+var packages = map[string][]string{
+	"github.com/artefactual-sdps/temporal-activities/bagcreate": {"Config"},
+}
+
+func main() {
+	docs := map[string]string{}
+	if err := parseDir(".", nil, docs); err != nil {
+		log.Fatal(err)
+	}
+	for pkg, types := range packages {
+		out, err := exec.Command("go", "list", "-f", "{{.Dir}}", pkg).Output()
+		if err != nil {
+			log.Fatalf("go list %s: %v", pkg, err)
+		}
+		if err := parseDir(strings.TrimSpace(string(out)), types, docs); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	keys := make([]string, 0, len(docs))
+	for k := range docs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	fmt.Fprint(&buf, "// Code generated by gen_docs.go; DO NOT EDIT.\n\n")
+	fmt.Fprint(&buf, "package config\n\n")
+	fmt.Fprint(&buf, "// fieldDocs are the doc comments of the configuration struct fields, by\n")
+	fmt.Fprint(&buf, "// package, type and field name, e.g. \"config.Temporal.Address\".\n")
+	fmt.Fprint(&buf, "var fieldDocs = map[string]string{\n")
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "\t%s: %s,\n", strconv.Quote(k), strconv.Quote(docs[k]))
+	}
+	fmt.Fprint(&buf, "}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("docs_gen.go", src, 0o600); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseDir adds the doc comments of the exported struct fields declared in
+// the non-test Go files of dir to docs. Only the fields of the given types are
+// added, or of all the types if types is nil.
+func parseDir(dir string, types []string, docs map[string]string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+
+	fset := token.NewFileSet()
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") || strings.HasSuffix(name, "_gen.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return err
+		}
+		if strings.HasSuffix(f.Name.Name, "_test") || f.Name.Name == "main" {
+			continue
+		}
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			ts, ok := n.(*ast.TypeSpec)
+			if !ok || !ts.Name.IsExported() {
+				return true
+			}
+			if types != nil && !slices.Contains(types, ts.Name.Name) {
+				return false
+			}
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return true
+			}
+
+			for _, field := range st.Fields.List {
+				if field.Doc == nil {
+					continue
+				}
+				for _, id := range field.Names {
+					if id.IsExported() {
+						docs[f.Name.Name+"."+ts.Name.Name+"."+id.Name] = text(field.Doc.Text())
+					}
+				}
+			}
+
+			return false
+		})
+	}
+
+	return nil
+}
+
+// text joins the lines of each paragraph of a doc comment.
+func text(doc string) string {
+	paragraphs := strings.Split(strings.TrimSpace(doc), "\n\n")
+	for i, p := range paragraphs {
+		paragraphs[i] = strings.Join(strings.Fields(p), " ")
+	}
+
+	return strings.Join(paragraphs, "\n\n")
+}
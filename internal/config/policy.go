@@ -0,0 +1,112 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/artefactual-sdps/temporal-activities/bagcreate"
+)
+
+// Override is a policy override block, it changes the preprocessing policy of
+// the transfers it matches. An override matches a transfer when all its
+// selectors, RelativePath and Profile, match.
+type Override struct {
+	// RelativePath is a glob pattern, e.g. "donor-a/*", matching the SharedPath
+	// relative path of the transfers (required if Profile is empty).
+	RelativePath string
+
+	// Profile matches the Profile workflow param of the transfers (required if
+	// RelativePath is empty).
+	Profile string
+
+	// Steps lists the names of the preprocessing workflow steps to run,
+	// replacing the configuration steps (optional).
+	Steps []string
+
+	// Bagit is the BagIt bag configuration of the matched transfers (optional).
+	Bagit bagcreate.Config
+}
+
+// matches reports whether o applies to the transfer at relativePath started
+// with the given profile.
+func (o Override) matches(relativePath, profile string) bool {
+	if o.Profile != "" && o.Profile != profile {
+		return false
+	}
+	if o.RelativePath != "" {
+		ok, err := path.Match(o.RelativePath, relativePath)
+		if err != nil || !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Policy is the preprocessing policy of a transfer.
+type Policy struct {
+	// Steps lists the names of the preprocessing workflow steps to run, all
+	// the steps run if it's empty.
+	Steps []string
+
+	// Bagit is the BagIt bag configuration of the transfer.
+	Bagit bagcreate.Config
+
+	// EventLog is the event log configuration of the worker that started the
+	// transfer workflow.
+	EventLog EventLogConfig
+
+	// Database reports whether the worker that started the transfer workflow
+	// saves the workflow run in the database.
+	Database bool
+}
+
+// ResolvePolicy returns the policy of the transfer at relativePath started
+// with the given profile: the configuration Steps and Bagit merged with the
+// settings of the matching overrides, in order, so the last override setting
+// wins. The EventLog and Database.Enabled settings can't be overridden.
+func (c Configuration) ResolvePolicy(relativePath, profile string) Policy {
+	p := Policy{
+		Steps:    c.Steps,
+		Bagit:    c.Bagit,
+		EventLog: c.EventLog,
+		Database: c.Database.Enabled,
+	}
+	for _, o := range c.Overrides {
+		if !o.matches(relativePath, profile) {
+			continue
+		}
+		if len(o.Steps) > 0 {
+			p.Steps = o.Steps
+		}
+		if o.Bagit != (bagcreate.Config{}) {
+			p.Bagit = o.Bagit
+		}
+	}
+
+	return p
+}
+
+func (c Configuration) validateOverrides() error {
+	var errs error
+
+	for i, o := range c.Overrides {
+		field := fmt.Sprintf("Overrides[%d]", i)
+
+		if o.RelativePath == "" && o.Profile == "" {
+			errs = errors.Join(errs, fmt.Errorf("%s: missing RelativePath or Profile value", field))
+		}
+		if _, err := path.Match(o.RelativePath, ""); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("%s.RelativePath: %q is not a valid glob pattern", field, o.RelativePath))
+		}
+
+		if o.Bagit != (bagcreate.Config{}) {
+			if err := o.Bagit.Validate(); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("%s.Bagit.%v", field, err))
+			}
+		}
+	}
+
+	return errs
+}
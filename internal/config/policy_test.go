@@ -0,0 +1,114 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/artefactual-sdps/temporal-activities/bagcreate"
+	"gotest.tools/v3/assert"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+func TestResolvePolicy(t *testing.T) {
+	t.Parallel()
+
+	eventLog := config.EventLogConfig{Enabled: true, Path: "preprocessing-events.jsonl"}
+	cfg := config.Configuration{
+		Steps:    []string{"bag-sip", "describe-sip"},
+		Bagit:    bagcreate.Config{ChecksumAlgorithm: "sha512"},
+		EventLog: eventLog,
+		Database: config.DatabaseConfig{Enabled: true, Driver: "sqlite"},
+		Overrides: []config.Override{
+			{
+				RelativePath: "donor-a/*",
+				Steps:        []string{"bag-sip"},
+			},
+			{
+				Profile: "strict",
+				Bagit:   bagcreate.Config{ChecksumAlgorithm: "sha256"},
+			},
+			{
+				RelativePath: "donor-a/*",
+				Profile:      "fast",
+				Bagit:        bagcreate.Config{ChecksumAlgorithm: "md5"},
+			},
+		},
+	}
+
+	for _, tc := range []struct {
+		name         string
+		relativePath string
+		profile      string
+		want         config.Policy
+	}{
+		{
+			name:         "Returns the configuration settings when no override matches",
+			relativePath: "donor-b/transfer",
+			want: config.Policy{
+				Steps:    []string{"bag-sip", "describe-sip"},
+				Bagit:    bagcreate.Config{ChecksumAlgorithm: "sha512"},
+				EventLog: eventLog,
+				Database: true,
+			},
+		},
+		{
+			name:         "Matches the relative path",
+			relativePath: "donor-a/transfer",
+			want: config.Policy{
+				Steps:    []string{"bag-sip"},
+				Bagit:    bagcreate.Config{ChecksumAlgorithm: "sha512"},
+				EventLog: eventLog,
+				Database: true,
+			},
+		},
+		{
+			name:         "Doesn't match subdirectories of the glob pattern",
+			relativePath: "donor-a/batch/transfer",
+			want: config.Policy{
+				Steps:    []string{"bag-sip", "describe-sip"},
+				Bagit:    bagcreate.Config{ChecksumAlgorithm: "sha512"},
+				EventLog: eventLog,
+				Database: true,
+			},
+		},
+		{
+			name:         "Matches the profile",
+			relativePath: "donor-b/transfer",
+			profile:      "strict",
+			want: config.Policy{
+				Steps:    []string{"bag-sip", "describe-sip"},
+				Bagit:    bagcreate.Config{ChecksumAlgorithm: "sha256"},
+				EventLog: eventLog,
+				Database: true,
+			},
+		},
+		{
+			name:         "Merges the matching overrides in order",
+			relativePath: "donor-a/transfer",
+			profile:      "fast",
+			want: config.Policy{
+				Steps:    []string{"bag-sip"},
+				Bagit:    bagcreate.Config{ChecksumAlgorithm: "md5"},
+				EventLog: eventLog,
+				Database: true,
+			},
+		},
+		{
+			name:         "Requires all the selectors to match",
+			relativePath: "donor-b/transfer",
+			profile:      "fast",
+			want: config.Policy{
+				Steps:    []string{"bag-sip", "describe-sip"},
+				Bagit:    bagcreate.Config{ChecksumAlgorithm: "sha512"},
+				EventLog: eventLog,
+				Database: true,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.DeepEqual(t, cfg.ResolvePolicy(tc.relativePath, tc.profile), tc.want)
+		})
+	}
+}
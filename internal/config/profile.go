@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/artefactual-sdps/temporal-activities/bagcreate"
+)
+
+// Profile is a workflow configuration served by its own Temporal worker, e.g.
+// the pipeline of a donor. The settings not in Profile are shared with the
+// top-level configuration, that is the default profile.
+type Profile struct {
+	// Name identifies the profile in the logs (required).
+	Name string
+
+	// TaskQueue is the Temporal task queue of the profile worker, it must be
+	// unique (required).
+	TaskQueue string
+
+	// WorkflowName is the name of the preprocessing workflow (required).
+	WorkflowName string
+
+	// BatchWorkflowName is the name of the batch workflow, the batch workflow
+	// is not registered if empty (optional).
+	BatchWorkflowName string
+
+	// Steps lists the names of the preprocessing workflow steps to run, all
+	// the steps run if it's empty (default: empty).
+	Steps []string
+
+	// Bagit is the BagIt bag configuration of the profile (default: the
+	// top-level Bagit configuration).
+	Bagit bagcreate.Config
+}
+
+// ProfileConfigs returns the configuration of each Temporal worker: the
+// top-level configuration followed by the configuration of each profile.
+func (c Configuration) ProfileConfigs() []Configuration {
+	configs := []Configuration{c}
+	for _, p := range c.Profiles {
+		pc := c
+		pc.Temporal.TaskQueue = p.TaskQueue
+		pc.Temporal.WorkflowName = p.WorkflowName
+		pc.Temporal.BatchWorkflowName = p.BatchWorkflowName
+		pc.Steps = p.Steps
+		if p.Bagit != (bagcreate.Config{}) {
+			pc.Bagit = p.Bagit
+		}
+		pc.Profiles = nil
+		configs = append(configs, pc)
+	}
+
+	return configs
+}
+
+func (c Configuration) validateProfiles() error {
+	var errs error
+
+	names := map[string]bool{}
+	queues := map[string]bool{c.Temporal.TaskQueue: true}
+	for i, p := range c.Profiles {
+		path := fmt.Sprintf("Profiles[%d]", i)
+
+		if p.Name == "" {
+			errs = errors.Join(errs, errRequired(path+".Name"))
+		} else if names[p.Name] {
+			errs = errors.Join(errs, fmt.Errorf("%s.Name: %q is used by another profile", path, p.Name))
+		}
+		names[p.Name] = true
+
+		if p.TaskQueue == "" {
+			errs = errors.Join(errs, errRequired(path+".TaskQueue"))
+		} else if queues[p.TaskQueue] {
+			errs = errors.Join(errs, fmt.Errorf("%s.TaskQueue: %q is used by another profile", path, p.TaskQueue))
+		}
+		queues[p.TaskQueue] = true
+
+		if p.WorkflowName == "" {
+			errs = errors.Join(errs, errRequired(path+".WorkflowName"))
+		} else if p.BatchWorkflowName == p.WorkflowName {
+			errs = errors.Join(errs, fmt.Errorf(
+				"%s.BatchWorkflowName: %q must be different from %s.WorkflowName",
+				path, p.BatchWorkflowName, path,
+			))
+		}
+
+		if p.Bagit != (bagcreate.Config{}) {
+			if err := p.Bagit.Validate(); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("%s.Bagit.%v", path, err))
+			}
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,61 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/artefactual-sdps/temporal-activities/bagcreate"
+	"gotest.tools/v3/assert"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+func TestProfileConfigs(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Configuration{
+		SharedPath: "/home/preprocessing/shared",
+		Temporal: config.Temporal{
+			TaskQueue:         "preprocessing",
+			WorkflowName:      "preprocessing",
+			BatchWorkflowName: "preprocessing-batch",
+		},
+		Bagit: bagcreate.Config{ChecksumAlgorithm: "sha512"},
+		Profiles: []config.Profile{
+			{
+				Name:         "donor-a",
+				TaskQueue:    "donor-a",
+				WorkflowName: "donor-a",
+				Steps:        []string{"bag-sip"},
+				Bagit:        bagcreate.Config{ChecksumAlgorithm: "md5"},
+			},
+			{
+				Name:              "donor-b",
+				TaskQueue:         "donor-b",
+				WorkflowName:      "donor-b",
+				BatchWorkflowName: "donor-b-batch",
+			},
+		},
+	}
+
+	configs := cfg.ProfileConfigs()
+	assert.Equal(t, len(configs), 3)
+	assert.DeepEqual(t, configs[0], cfg)
+	assert.DeepEqual(t, configs[1], config.Configuration{
+		SharedPath: "/home/preprocessing/shared",
+		Steps:      []string{"bag-sip"},
+		Temporal: config.Temporal{
+			TaskQueue:    "donor-a",
+			WorkflowName: "donor-a",
+		},
+		Bagit: bagcreate.Config{ChecksumAlgorithm: "md5"},
+	})
+	assert.DeepEqual(t, configs[2], config.Configuration{
+		SharedPath: "/home/preprocessing/shared",
+		Temporal: config.Temporal{
+			TaskQueue:         "donor-b",
+			WorkflowName:      "donor-b",
+			BatchWorkflowName: "donor-b-batch",
+		},
+		Bagit: bagcreate.Config{ChecksumAlgorithm: "sha512"},
+	})
+}
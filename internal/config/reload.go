@@ -0,0 +1,109 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+)
+
+// reloadable lists the Configuration fields that can change while the worker
+// is running, by path without the slice indexes, e.g. "Profiles.Bagit".
+// Changes to any other field need a worker restart.
+var reloadable = map[string]bool{
+	"Steps":          true,
+	"Bagit":          true,
+	"Overrides":      true,
+	"Profiles.Steps": true,
+	"Profiles.Bagit": true,
+}
+
+// Store holds the current configuration of a running worker, it's safe for
+// concurrent use.
+type Store struct {
+	mu  sync.Mutex
+	cfg atomic.Pointer[Configuration]
+}
+
+func NewStore(cfg Configuration) *Store {
+	s := &Store{}
+	s.cfg.Store(&cfg)
+
+	return s
+}
+
+// Load returns the current configuration.
+func (s *Store) Load() Configuration {
+	return *s.cfg.Load()
+}
+
+// Reload swaps in the reloadable fields of next and returns the paths of the
+// fields that changed but need a worker restart, e.g. "Temporal.Address" or
+// "Profiles[0].TaskQueue". next must be a valid configuration.
+func (s *Store) Reload(next Configuration) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := s.Load()
+	restart := reload(reflect.ValueOf(&updated).Elem(), reflect.ValueOf(next), "", "")
+	s.cfg.Store(&updated)
+
+	return restart
+}
+
+// reload sets the reloadable fields of v, the current value of the field at
+// path, to the fields of next. It returns the paths of the other fields that
+// differ between v and next. key is path without the slice indexes.
+func reload(v, next reflect.Value, path, key string) []string {
+	if reloadable[key] {
+		v.Set(next)
+		return nil
+	}
+
+	switch {
+	case v.Kind() == reflect.Struct:
+		var paths []string
+		for i := range v.NumField() {
+			f := v.Type().Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			paths = append(paths, reload(v.Field(i), next.Field(i), join(path, f.Name), join(key, f.Name))...)
+		}
+		return paths
+	case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Struct && v.Len() > 0 && v.Len() == next.Len():
+		// Copy the slice, it's shared with the previous configuration.
+		s := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		reflect.Copy(s, v)
+		v.Set(s)
+
+		var paths []string
+		for i := range v.Len() {
+			paths = append(paths, reload(v.Index(i), next.Index(i), fmt.Sprintf("%s[%d]", path, i), key)...)
+		}
+		return paths
+	}
+
+	if reflect.DeepEqual(v.Interface(), next.Interface()) {
+		return nil
+	}
+
+	return []string{path}
+}
+
+// Watch watches configFile for changes and calls onChange with the
+// configuration read after each change, or with the error reading or
+// validating it.
+func Watch(configFile string, onChange func(Configuration, error)) {
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	v.OnConfigChange(func(fsnotify.Event) {
+		var cfg Configuration
+		_, _, err := Read(&cfg, configFile)
+		onChange(cfg, err)
+	})
+	v.WatchConfig()
+}
@@ -0,0 +1,133 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/artefactual-sdps/temporal-activities/bagcreate"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+func TestStoreReload(t *testing.T) {
+	t.Parallel()
+
+	cur := config.Configuration{
+		SharedPath: "/home/preprocessing/shared",
+		Steps:      []string{"bag-sip", "describe-sip"},
+		Temporal: config.Temporal{
+			Address:   "localhost:7233",
+			TaskQueue: "preprocessing",
+		},
+		Bagit: bagcreate.Config{ChecksumAlgorithm: "sha512"},
+		Profiles: []config.Profile{
+			{Name: "donor-a", TaskQueue: "preprocessing-donor-a", WorkflowName: "preprocessing-donor-a"},
+		},
+	}
+	store := config.NewStore(cur)
+
+	next := cur
+	next.Steps = []string{"bag-sip"}
+	next.Temporal.Address = "temporal:7233"
+	next.Messages.Catalogs = map[string]string{"fr": "fr.json"}
+	next.Bagit.ChecksumAlgorithm = "md5"
+	next.Overrides = []config.Override{{RelativePath: "donor-b/*", Steps: []string{"bag-sip"}}}
+	next.Profiles = []config.Profile{{
+		Name:         "donor-a",
+		TaskQueue:    "preprocessing-donor-b",
+		WorkflowName: "preprocessing-donor-a",
+		Steps:        []string{"describe-sip"},
+		Bagit:        bagcreate.Config{ChecksumAlgorithm: "sha256"},
+	}}
+
+	restart := store.Reload(next)
+	assert.DeepEqual(t, restart, []string{"Temporal.Address", "Messages.Catalogs", "Profiles[0].TaskQueue"})
+
+	// Only the reloadable fields are swapped in.
+	want := cur
+	want.Steps = []string{"bag-sip"}
+	want.Bagit.ChecksumAlgorithm = "md5"
+	want.Overrides = []config.Override{{RelativePath: "donor-b/*", Steps: []string{"bag-sip"}}}
+	want.Profiles = []config.Profile{{
+		Name:         "donor-a",
+		TaskQueue:    "preprocessing-donor-a",
+		WorkflowName: "preprocessing-donor-a",
+		Steps:        []string{"describe-sip"},
+		Bagit:        bagcreate.Config{ChecksumAlgorithm: "sha256"},
+	}}
+	assert.DeepEqual(t, store.Load(), want)
+
+	// The previous configuration is not modified.
+	assert.Equal(t, len(cur.Profiles[0].Steps), 0)
+
+	// Reloading the same configuration doesn't need a restart.
+	assert.Equal(t, len(store.Reload(store.Load())), 0)
+
+	// Adding or removing a profile needs a restart.
+	next = store.Load()
+	next.Profiles = nil
+	assert.DeepEqual(t, store.Reload(next), []string{"Profiles"})
+}
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	toml := func(alg string) string {
+		return `sharedPath = "/home/preprocessing/shared"
+[temporal]
+taskQueue = "preprocessing"
+workflowName = "preprocessing"
+[bagit]
+checksumAlgorithm = "` + alg + `"
+`
+	}
+	dir := fs.NewDir(t, "preprocessing-test", fs.WithFile("preprocessing.toml", toml("sha512")))
+
+	type change struct {
+		cfg config.Configuration
+		err error
+	}
+	changes := make(chan change, 10)
+	config.Watch(dir.Join("preprocessing.toml"), func(cfg config.Configuration, err error) {
+		changes <- change{cfg, err}
+	})
+
+	next := func() change {
+		t.Helper()
+		select {
+		case c := <-changes:
+			return c
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a configuration change")
+			return change{}
+		}
+	}
+
+	// Replace the file atomically, like Kubernetes does with mounted secrets,
+	// so the watcher doesn't read a partially written file.
+	write := func(content string) {
+		t.Helper()
+		tmp := dir.Join("preprocessing.toml.tmp")
+		assert.NilError(t, os.WriteFile(tmp, []byte(content), 0o600))
+		assert.NilError(t, os.Rename(tmp, dir.Join("preprocessing.toml")))
+	}
+
+	// A change may be notified more than once, check the last one.
+	write(toml("md5"))
+	c := next()
+	for c.err == nil && c.cfg.Bagit.ChecksumAlgorithm != "md5" {
+		c = next()
+	}
+	assert.NilError(t, c.err)
+	assert.Equal(t, c.cfg.Bagit.ChecksumAlgorithm, "md5")
+
+	write(toml("unknown"))
+	c = next()
+	for c.err == nil {
+		c = next()
+	}
+	assert.ErrorContains(t, c.err, `Bagit.ChecksumAlgorithm: invalid value "unknown"`)
+}
@@ -0,0 +1,222 @@
+package config
+
+//go:generate go run gen_docs.go
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// required lists the settings that must be set, the fields of the profiles
+// are listed without index, e.g. "Profiles.Name".
+var required = map[string]bool{
+	"SharedPath":            true,
+	"Temporal.TaskQueue":    true,
+	"Temporal.WorkflowName": true,
+	"Profiles.Name":         true,
+	"Profiles.TaskQueue":    true,
+	"Profiles.WorkflowName": true,
+}
+
+// Schema returns the JSON Schema of the configuration file, with the
+// descriptions taken from the doc comments of the Configuration fields.
+func Schema() map[string]any {
+	s := typeSchema(reflect.TypeOf(Configuration{}), "")
+	s["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	s["title"] = "Preprocessing worker configuration"
+
+	return s
+}
+
+func typeSchema(t reflect.Type, path string) map[string]any {
+	switch t {
+	case byteSizeType:
+		return map[string]any{"type": []string{"integer", "string"}, "minimum": 0}
+	case durationType:
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		props := map[string]any{}
+		var req []string
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+
+			key := join(path, f.Name)
+			name := lowerCamel(f.Name)
+
+			s := typeSchema(f.Type, key)
+			if doc := fieldDocs[docKey(t, f.Name)]; doc != "" {
+				s["description"] = doc
+			}
+			if v, ok := defaults[key]; ok {
+				s["default"] = jsonValue(v)
+			}
+			if secrets[key] {
+				s["writeOnly"] = true
+			}
+			props[name] = s
+
+			// Tables with required settings are required.
+			if required[key] || (f.Type.Kind() == reflect.Struct && s["required"] != nil) {
+				req = append(req, name)
+			}
+		}
+
+		s := map[string]any{"type": "object", "properties": props}
+		if req != nil {
+			s["required"] = req
+		}
+		return s
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": typeSchema(t.Elem(), path)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": typeSchema(t.Elem(), path)}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	default:
+		return map[string]any{"type": "string"}
+	}
+}
+
+// Reference returns the Markdown reference of the configuration settings.
+func Reference() string {
+	var b strings.Builder
+
+	b.WriteString(`# Configuration reference
+
+<!-- Code generated by "preprocessing-worker config schema --format markdown"; DO NOT EDIT. -->
+
+The worker reads its configuration from the TOML file given with the
+` + "`--config`" + ` flag, or from the first ` + "`preprocessing.toml`" + ` file found in the
+current directory, ` + "`$HOME/.config`" + ` or ` + "`/etc`" + `. The settings can also be set with
+the environment variables listed below. String settings can be read from a
+file with a ` + "`file://`" + ` value or with the ` + "`_FILE`" + ` suffixed environment variable.
+Byte sizes are set in bytes or with a unit, e.g. ` + "`\"512MB\"`" + ` or ` + "`\"10GiB\"`" + `, and
+durations as Go durations, e.g. ` + "`\"30s\"`" + `.
+
+## Top-level settings
+`)
+	reference(&b, reflect.TypeOf(Configuration{}), "", "", true)
+
+	return b.String()
+}
+
+// reference writes the reference of the settings of the struct t, the table
+// tomlKey, followed by the reference of its tables. The settings in arrays of
+// tables can't be set with environment variables.
+func reference(b *strings.Builder, t reflect.Type, path, tomlKey string, env bool) {
+	type table struct {
+		t       reflect.Type
+		path    string
+		tomlKey string
+		env     bool
+		doc     string
+	}
+	var tables []table
+
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		key := join(path, f.Name)
+		name := join(tomlKey, lowerCamel(f.Name))
+		doc := fieldDocs[docKey(t, f.Name)]
+
+		switch {
+		case f.Type.Kind() == reflect.Struct:
+			tables = append(tables, table{f.Type, key, name, env, doc})
+			continue
+		case f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Struct:
+			tables = append(tables, table{f.Type.Elem(), key, name + "[]", false, doc})
+			continue
+		}
+
+		fmt.Fprintf(b, "\n### `%s`\n\n", name)
+		if doc != "" {
+			fmt.Fprintf(b, "%s\n\n", doc)
+		}
+		fmt.Fprintf(b, "- Type: %s\n", typeName(f.Type))
+		if required[key] {
+			b.WriteString("- Required: yes\n")
+		}
+		if v, ok := defaults[key]; ok {
+			d, _ := json.Marshal(jsonValue(v))
+			fmt.Fprintf(b, "- Default: `%s`\n", d)
+		}
+		if secrets[key] {
+			b.WriteString("- Secret: yes, redacted by `config print`\n")
+		}
+		if env && fromEnv(reflect.New(f.Type).Elem()) {
+			fmt.Fprintf(b, "- Environment variable: `%s`\n", EnvName(key))
+		}
+	}
+
+	for _, tb := range tables {
+		fmt.Fprintf(b, "\n## `%s`\n", tb.tomlKey)
+		if tb.doc != "" {
+			fmt.Fprintf(b, "\n%s\n", tb.doc)
+		}
+		reference(b, tb.t, tb.path, tb.tomlKey, tb.env)
+	}
+}
+
+// jsonValue returns the JSON value of the setting value v, the durations are
+// set as strings in the configuration.
+func jsonValue(v any) any {
+	if d, ok := v.(time.Duration); ok {
+		return d.String()
+	}
+
+	return v
+}
+
+func typeName(t reflect.Type) string {
+	switch t {
+	case byteSizeType:
+		return "byte size, e.g. `\"10GiB\"`"
+	case durationType:
+		return "duration, e.g. `\"30s\"`"
+	}
+
+	switch t.Kind() {
+	case reflect.Slice:
+		return "array of " + typeName(t.Elem())
+	case reflect.Map:
+		return "table of " + typeName(t.Elem())
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	default:
+		return "string"
+	}
+}
+
+// docKey returns the fieldDocs key of the field of t, e.g.
+// "config.Temporal.Address".
+func docKey(t reflect.Type, field string) string {
+	return t.String() + "." + field
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
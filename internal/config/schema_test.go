@@ -0,0 +1,73 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	s := config.Schema()
+	assert.Equal(t, s["$schema"], "https://json-schema.org/draft/2020-12/schema")
+	assert.DeepEqual(t, s["required"], []string{"sharedPath", "temporal"})
+
+	props := s["properties"].(map[string]any)
+	temporal := props["temporal"].(map[string]any)
+	assert.DeepEqual(t, temporal["required"], []string{"taskQueue", "workflowName"})
+
+	tprops := temporal["properties"].(map[string]any)
+	assert.DeepEqual(t, tprops["batchWorkflowName"], map[string]any{
+		"type":    "string",
+		"default": "preprocessing-batch",
+		"description": "BatchWorkflowName is the name of the Temporal workflow that preprocesses a batch of " +
+			`transfers, starting a child workflow for each of them (default: "preprocessing-batch").`,
+	})
+	assert.Equal(t, tprops["apiKey"].(map[string]any)["writeOnly"], true)
+
+	profiles := props["profiles"].(map[string]any)
+	assert.Equal(t, profiles["type"], "array")
+	assert.DeepEqual(t,
+		profiles["items"].(map[string]any)["required"],
+		[]string{"name", "taskQueue", "workflowName"},
+	)
+}
+
+// TestSchemaDescriptions checks that every setting is documented, run
+// "make gen-config-docs" after changing the configuration structs.
+func TestSchemaDescriptions(t *testing.T) {
+	t.Parallel()
+
+	var check func(path string, s map[string]any)
+	check = func(path string, s map[string]any) {
+		if path != "" {
+			assert.Check(t, s["description"] != nil, "%s: missing description", path)
+		}
+		if items, ok := s["items"].(map[string]any); ok {
+			s = items
+		}
+		props, _ := s["properties"].(map[string]any)
+		for name, p := range props {
+			key := name
+			if path != "" {
+				key = path + "." + name
+			}
+			check(key, p.(map[string]any))
+		}
+	}
+	check("", config.Schema())
+}
+
+// TestReference checks that docs/configuration.md is up to date, run
+// "make gen-config-docs" to update it.
+func TestReference(t *testing.T) {
+	t.Parallel()
+
+	b, err := os.ReadFile("../../docs/configuration.md")
+	assert.NilError(t, err)
+	assert.Equal(t, string(b), config.Reference())
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// errFreeSpaceUnsupported is returned by freeSpace on the platforms where the
+// free space of a filesystem can't be checked.
+var errFreeSpaceUnsupported = errors.New("free space check not supported")
+
+// freeSpace returns the bytes available in the filesystem of path, it's
+// replaced in tests.
+var freeSpace = filesystemFreeSpace
+
+// CheckSharedPath verifies that SharedPath is a directory the worker can read
+// and write, on a filesystem with at least Worker.MinFreeSpace bytes free.
+// It's checked when the worker starts, unlike Validate it accesses the
+// filesystem.
+func (c Configuration) CheckSharedPath() error {
+	fi, err := os.Stat(c.SharedPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("SharedPath: %q does not exist", c.SharedPath)
+	} else if err != nil {
+		return fmt.Errorf("SharedPath: %v", err)
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("SharedPath: %q is not a directory", c.SharedPath)
+	}
+
+	var errs error
+
+	if _, err := os.ReadDir(c.SharedPath); err != nil {
+		errs = errors.Join(errs, fmt.Errorf("SharedPath: %q is not readable: %v", c.SharedPath, err))
+	}
+
+	f, err := os.CreateTemp(c.SharedPath, ".preprocessing-check-*")
+	if err != nil {
+		errs = errors.Join(errs, fmt.Errorf("SharedPath: %q is not writable: %v", c.SharedPath, err))
+	} else {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}
+
+	if c.Worker.MinFreeSpace > 0 {
+		free, err := freeSpace(c.SharedPath)
+		if errors.Is(err, errFreeSpaceUnsupported) {
+			errs = errors.Join(errs, fmt.Errorf(
+				"SharedPath: %v on this platform, unset Worker.MinFreeSpace to disable it",
+				err,
+			))
+		} else if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("SharedPath: check free space: %v", err))
+		} else if ByteSize(free) < c.Worker.MinFreeSpace {
+			errs = errors.Join(errs, fmt.Errorf(
+				"SharedPath: %s free space is less than Worker.MinFreeSpace (%s)",
+				ByteSize(free),
+				c.Worker.MinFreeSpace,
+			))
+		}
+	}
+
+	return errs
+}
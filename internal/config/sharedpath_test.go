@@ -0,0 +1,96 @@
+package config_test
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+func TestCheckSharedPath(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Passes with a writable directory", func(t *testing.T) {
+		t.Parallel()
+
+		dir := fs.NewDir(t, "preprocessing-test")
+		c := config.Configuration{SharedPath: dir.Path()}
+		c.Worker.MinFreeSpace = 1
+
+		assert.NilError(t, c.CheckSharedPath())
+
+		// The write check doesn't leave files behind.
+		entries, err := os.ReadDir(dir.Path())
+		assert.NilError(t, err)
+		assert.Equal(t, len(entries), 0)
+	})
+
+	t.Run("Errors when the path does not exist", func(t *testing.T) {
+		t.Parallel()
+
+		path := fs.NewDir(t, "preprocessing-test").Join("missing")
+		c := config.Configuration{SharedPath: path}
+
+		assert.Error(t, c.CheckSharedPath(), fmt.Sprintf("SharedPath: %q does not exist", path))
+	})
+
+	t.Run("Errors when the path is not a directory", func(t *testing.T) {
+		t.Parallel()
+
+		path := fs.NewDir(t, "preprocessing-test", fs.WithFile("file", "")).Join("file")
+		c := config.Configuration{SharedPath: path}
+
+		assert.Error(t, c.CheckSharedPath(), fmt.Sprintf("SharedPath: %q is not a directory", path))
+	})
+
+	t.Run("Errors when the directory is not writable", func(t *testing.T) {
+		t.Parallel()
+
+		if os.Geteuid() == 0 {
+			t.Skip("root can write to read-only directories")
+		}
+
+		dir := fs.NewDir(t, "preprocessing-test", fs.WithMode(0o500))
+		c := config.Configuration{SharedPath: dir.Path()}
+
+		assert.ErrorContains(t, c.CheckSharedPath(), fmt.Sprintf("SharedPath: %q is not writable: ", dir.Path()))
+	})
+
+	t.Run("Errors when the free space is less than the minimum", func(t *testing.T) {
+		t.Parallel()
+
+		dir := fs.NewDir(t, "preprocessing-test")
+		c := config.Configuration{SharedPath: dir.Path()}
+		c.Worker.MinFreeSpace = math.MaxUint64
+
+		assert.ErrorContains(t, c.CheckSharedPath(), "free space is less than Worker.MinFreeSpace (16 EiB)")
+	})
+}
+
+// TestCheckSharedPathUnsupported replaces the free space check, so it can't
+// run in parallel.
+func TestCheckSharedPathUnsupported(t *testing.T) {
+	config.SetFreeSpace(t, func(string) (uint64, error) {
+		return 0, config.ErrFreeSpaceUnsupported
+	})
+
+	t.Run("Errors when the free space can't be checked", func(t *testing.T) {
+		c := config.Configuration{SharedPath: fs.NewDir(t, "preprocessing-test").Path()}
+		c.Worker.MinFreeSpace = 1
+
+		assert.Error(t, c.CheckSharedPath(),
+			"SharedPath: free space check not supported on this platform, unset Worker.MinFreeSpace to disable it",
+		)
+	})
+
+	t.Run("Passes when the minimum free space is not set", func(t *testing.T) {
+		c := config.Configuration{SharedPath: fs.NewDir(t, "preprocessing-test").Path()}
+
+		assert.NilError(t, c.CheckSharedPath())
+	})
+}
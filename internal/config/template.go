@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// examples are the values of the required settings in DefaultsTemplate, the
+// fields of the arrays of tables are listed without index, e.g.
+// "Profiles.Name".
+var examples = map[string]any{
+	"SharedPath":             "/home/preprocessing/shared",
+	"Temporal.TaskQueue":     "preprocessing",
+	"Temporal.WorkflowName":  "preprocessing",
+	"Profiles.Name":          "donor-a",
+	"Profiles.TaskQueue":     "preprocessing-donor-a",
+	"Profiles.WorkflowName":  "preprocessing-donor-a",
+	"Overrides.RelativePath": "donor-b/*",
+}
+
+// DefaultsTemplate is a commented TOML configuration file with the default
+// values of the settings, generated from the Configuration fields and their
+// doc comments.
+var DefaultsTemplate = defaultsTemplate()
+
+func defaultsTemplate() string {
+	var b strings.Builder
+
+	var noEnv []string
+	walk(reflect.ValueOf(Configuration{}), "", func(key string, v reflect.Value) {
+		if !fromEnv(v) {
+			noEnv = append(noEnv, TOMLKey(key))
+		}
+	})
+
+	writeComment(&b, "", fmt.Sprintf(`Preprocessing worker configuration.
+
+The commented settings show their default values. Every setting can be overridden with an environment variable, e.g. %s or %s, except for %s and %s.
+
+String settings can be read from a file with a "file://" value, e.g. "file:///run/secrets/api-key", or with a _FILE suffixed environment variable, e.g. %s_FILE.`,
+		EnvName("Debug"),
+		EnvName("Temporal.Address"),
+		strings.Join(noEnv[:len(noEnv)-1], ", "),
+		noEnv[len(noEnv)-1],
+		EnvName("Temporal.APIKey"),
+	))
+	writeTemplate(&b, reflect.TypeOf(Configuration{}), "", "")
+
+	return b.String()
+}
+
+// writeTemplate writes the settings of the struct t, the table tomlKey, with
+// their doc comments, followed by its tables and arrays of tables. The
+// required settings are set to their example value, the other settings are
+// commented with their default value.
+func writeTemplate(b *strings.Builder, t reflect.Type, path, tomlKey string) {
+	var tables, arrays []reflect.StructField
+	first := true
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		switch {
+		case f.Type.Kind() == reflect.Struct, f.Type.Kind() == reflect.Map:
+			tables = append(tables, f)
+			continue
+		case f.Type.Kind() == reflect.Slice && f.Type.Elem().Kind() == reflect.Struct:
+			arrays = append(arrays, f)
+			continue
+		}
+
+		// The first setting of a table follows its header.
+		if !first || path == "" {
+			b.WriteString("\n")
+		}
+		first = false
+
+		key := join(path, f.Name)
+		writeComment(b, "", fieldDocs[docKey(t, f.Name)])
+		if v, ok := examples[key]; ok && required[key] {
+			fmt.Fprintf(b, "%s = %s\n", lowerCamel(f.Name), TOMLValue(v))
+		} else {
+			fmt.Fprintf(b, "# %s = %s\n", lowerCamel(f.Name), TOMLValue(defaultValue(f.Type, key)))
+		}
+	}
+
+	for _, f := range tables {
+		name := join(tomlKey, lowerCamel(f.Name))
+		b.WriteString("\n")
+		writeComment(b, "", fieldDocs[docKey(t, f.Name)])
+		fmt.Fprintf(b, "[%s]\n", name)
+		if f.Type.Kind() == reflect.Struct {
+			writeTemplate(b, f.Type, join(path, f.Name), name)
+		}
+	}
+
+	// The arrays of tables are optional, write a commented example.
+	for _, f := range arrays {
+		name := join(tomlKey, lowerCamel(f.Name))
+		b.WriteString("\n")
+		writeComment(b, "", fieldDocs[docKey(t, f.Name)]+" For example:")
+		b.WriteString("#\n")
+		fmt.Fprintf(b, "#   [[%s]]\n", name)
+		writeExample(b, f.Type.Elem(), join(path, f.Name), name)
+	}
+}
+
+// writeExample writes the commented settings of the struct t, an element of
+// the array of tables tomlKey, set to their example or default value.
+func writeExample(b *strings.Builder, t reflect.Type, path, tomlKey string) {
+	var tables []reflect.StructField
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Type.Kind() == reflect.Struct {
+			tables = append(tables, f)
+			continue
+		}
+
+		key := join(path, f.Name)
+		v, ok := examples[key]
+		if !ok {
+			v = defaultValue(f.Type, key)
+		}
+		fmt.Fprintf(b, "#   %s = %s\n", lowerCamel(f.Name), TOMLValue(v))
+	}
+
+	for _, f := range tables {
+		name := join(tomlKey, lowerCamel(f.Name))
+		fmt.Fprintf(b, "#\n#   [%s]\n", name)
+		writeExample(b, f.Type, join(path, f.Name), name)
+	}
+}
+
+// defaultValue returns the default value of the setting key of type t.
+func defaultValue(t reflect.Type, key string) any {
+	if v, ok := defaults[key]; ok {
+		return v
+	}
+
+	return reflect.Zero(t).Interface()
+}
+
+// writeComment writes text as TOML comment lines of up to 80 characters,
+// prefixed with prefix.
+func writeComment(b *strings.Builder, prefix, text string) {
+	for i, p := range strings.Split(text, "\n\n") {
+		if i > 0 {
+			fmt.Fprintf(b, "%s#\n", prefix)
+		}
+
+		line := prefix + "#"
+		for _, w := range strings.Fields(p) {
+			if len(line)+1+len(w) > 80 && line != prefix+"#" {
+				fmt.Fprintf(b, "%s\n", line)
+				line = prefix + "#"
+			}
+			line += " " + w
+		}
+		fmt.Fprintf(b, "%s\n", line)
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+type TLSConfig struct {
+	// Enabled toggles the TLS connection to the Temporal server (default:
+	// false).
+	Enabled bool
+
+	// CACert is the path of the PEM encoded CA certificates that verify the
+	// server certificate, the system root CAs are used if empty (optional).
+	CACert string
+
+	// ClientCert is the path of the PEM encoded client certificate for mutual
+	// TLS, it requires ClientKey (optional).
+	ClientCert string
+
+	// ClientKey is the path of the PEM encoded private key of ClientCert
+	// (optional).
+	ClientKey string
+
+	// ServerName is the name used to verify the server certificate, when it
+	// doesn't match the host of Temporal.Address (optional).
+	ServerName string
+}
+
+// Load returns the TLS configuration of the Temporal client, loading the
+// certificate files, or nil if TLS is not enabled.
+func (c TLSConfig) Load() (*tls.Config, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+
+	tc := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.ServerName,
+	}
+
+	if c.CACert != "" {
+		pem, err := os.ReadFile(c.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("CACert: %v", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CACert: no certificates found in %q", c.CACert)
+		}
+		tc.RootCAs = pool
+	}
+
+	if c.ClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(c.ClientCert, c.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("ClientCert: %v", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+
+	return tc, nil
+}
+
+func (c TLSConfig) validate() error {
+	var errs error
+
+	if !c.Enabled {
+		if c.CACert != "" || c.ClientCert != "" || c.ClientKey != "" || c.ServerName != "" {
+			errs = errors.Join(errs, errors.New("Temporal.TLS.Enabled: must be true to use the TLS settings"))
+		}
+		return errs
+	}
+
+	if c.ClientCert != "" && c.ClientKey == "" {
+		errs = errors.Join(errs, errors.New("Temporal.TLS.ClientKey: missing required value, ClientCert is set"))
+	}
+	if c.ClientKey != "" && c.ClientCert == "" {
+		errs = errors.Join(errs, errors.New("Temporal.TLS.ClientCert: missing required value, ClientKey is set"))
+	}
+	if errs != nil {
+		return errs
+	}
+
+	if _, err := c.Load(); err != nil {
+		errs = errors.Join(errs, fmt.Errorf("Temporal.TLS.%v", err))
+	}
+
+	return errs
+}
@@ -0,0 +1,131 @@
+package config_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/artefactual-sdps/temporal-activities/bagcreate"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+// certFiles returns a directory with a self-signed certificate, "cert.pem",
+// and its private key, "key.pem".
+func certFiles(t *testing.T) *fs.Dir {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "temporal"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	assert.NilError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NilError(t, err)
+
+	return fs.NewDir(t, "preprocessing-tls",
+		fs.WithFile("cert.pem", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))),
+		fs.WithFile("key.pem", string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))),
+		fs.WithFile("empty.pem", ""),
+	)
+}
+
+func TestTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := certFiles(t)
+
+	t.Run("Returns nil when TLS is not enabled", func(t *testing.T) {
+		t.Parallel()
+
+		tc, err := config.TLSConfig{}.Load()
+		assert.NilError(t, err)
+		assert.Assert(t, tc == nil)
+	})
+
+	t.Run("Loads the certificates", func(t *testing.T) {
+		t.Parallel()
+
+		tc, err := config.TLSConfig{
+			Enabled:    true,
+			CACert:     dir.Join("cert.pem"),
+			ClientCert: dir.Join("cert.pem"),
+			ClientKey:  dir.Join("key.pem"),
+			ServerName: "temporal",
+		}.Load()
+		assert.NilError(t, err)
+		assert.Assert(t, tc.RootCAs != nil)
+		assert.Equal(t, len(tc.Certificates), 1)
+		assert.Equal(t, tc.ServerName, "temporal")
+	})
+
+	for _, tc := range []struct {
+		name    string
+		tls     config.TLSConfig
+		wantErr string
+	}{
+		{
+			name:    "Errors when TLS settings are set but not enabled",
+			tls:     config.TLSConfig{CACert: dir.Join("cert.pem")},
+			wantErr: "Temporal.TLS.Enabled: must be true to use the TLS settings",
+		},
+		{
+			name:    "Errors when the client key is missing",
+			tls:     config.TLSConfig{Enabled: true, ClientCert: dir.Join("cert.pem")},
+			wantErr: "Temporal.TLS.ClientKey: missing required value, ClientCert is set",
+		},
+		{
+			name:    "Errors when the client certificate is missing",
+			tls:     config.TLSConfig{Enabled: true, ClientKey: dir.Join("key.pem")},
+			wantErr: "Temporal.TLS.ClientCert: missing required value, ClientKey is set",
+		},
+		{
+			name:    "Errors when the CA file has no certificates",
+			tls:     config.TLSConfig{Enabled: true, CACert: dir.Join("empty.pem")},
+			wantErr: `Temporal.TLS.CACert: no certificates found in "` + dir.Join("empty.pem") + `"`,
+		},
+		{
+			name:    "Errors when the client key file has no key",
+			tls:     config.TLSConfig{Enabled: true, ClientCert: dir.Join("cert.pem"), ClientKey: dir.Join("cert.pem")},
+			wantErr: "Temporal.TLS.ClientCert: tls: found a certificate rather than a key in the PEM for the private key",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := config.Configuration{
+				SharedPath: "/home/preprocessing/shared",
+				Temporal: config.Temporal{
+					TaskQueue:         "preprocessing",
+					WorkflowName:      "preprocessing",
+					BatchWorkflowName: "preprocessing-batch",
+					TLS:               tc.tls,
+				},
+				Worker: config.WorkerConfig{
+					MaxConcurrentSessions: 1,
+					MaxBatchConcurrency:   1,
+				},
+				Bagit: bagcreate.Config{ChecksumAlgorithm: "sha512"},
+			}
+
+			assert.Error(t, cfg.Validate(), tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+)
+
+// ByteSize is a size in bytes. It's set in the configuration as a number of
+// bytes or as a string with a unit, e.g. "512MB" or "10GiB".
+type ByteSize uint64
+
+// String returns the size with a binary unit, e.g. "10 GiB".
+func (s ByteSize) String() string {
+	return humanize.IBytes(uint64(s))
+}
+
+var (
+	byteSizeType = reflect.TypeOf(ByteSize(0))
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// decodeHook converts the string values of the ByteSize and time.Duration
+// settings, and splits the comma separated string values of the slice
+// settings like the viper default hook.
+func decodeHook() viper.DecoderConfigOption {
+	return viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		stringToByteSizeHookFunc,
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	))
+}
+
+func stringToByteSizeHookFunc(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to != byteSizeType {
+		return data, nil
+	}
+
+	n, err := humanize.ParseBytes(data.(string))
+
+	return ByteSize(n), err
+}
+
+// checkUnits verifies the values of the ByteSize and time.Duration settings
+// of v before decoding them, to report the invalid values with their key. The
+// invalid values are reset to zero so the other settings can be decoded and
+// validated.
+func checkUnits(v *viper.Viper) error {
+	var errs error
+	walk(reflect.ValueOf(Configuration{}), "", func(key string, fv reflect.Value) {
+		if fv.Type() != byteSizeType && fv.Type() != durationType {
+			return
+		}
+
+		value := reflect.ValueOf(v.Get(key))
+		if value.IsValid() && value.Type() == fv.Type() {
+			// The default value.
+			return
+		}
+
+		var err error
+		switch value.Kind() {
+		case reflect.String:
+			if fv.Type() == byteSizeType {
+				_, err = humanize.ParseBytes(value.String())
+			} else {
+				_, err = time.ParseDuration(value.String())
+			}
+			if err != nil {
+				err = fmt.Errorf("%s: %q is not a valid %s", key, value.String(), unitsHelp(fv.Type()))
+			}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if fv.Type() == durationType {
+				// A number of nanoseconds is most likely a missing unit.
+				err = fmt.Errorf("%s: %d is not a valid %s", key, value.Int(), unitsHelp(fv.Type()))
+			} else if value.Int() < 0 {
+				err = fmt.Errorf("%s: %d is less than the minimum value (0)", key, value.Int())
+			}
+		}
+		if err != nil {
+			errs = errors.Join(errs, err)
+			v.Set(key, fv.Interface())
+		}
+	})
+
+	return errs
+}
+
+// unitsHelp describes the values of a setting of type t with units.
+func unitsHelp(t reflect.Type) string {
+	if t == byteSizeType {
+		return `byte size, e.g. "512MB" or "10GiB"`
+	}
+
+	return `duration, e.g. "30s" or "1h30m"`
+}
@@ -0,0 +1,206 @@
+package config_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+const unitsConfig = `sharedPath = "/home/preprocessing/shared"
+[temporal]
+taskQueue = "preprocessing"
+workflowName = "preprocessing"
+[worker]
+%s = %s
+`
+
+func TestByteSize(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		value   string
+		want    config.ByteSize
+		wantErr string
+	}{
+		{
+			name:  "Reads a number of bytes",
+			value: "1024",
+			want:  1024,
+		},
+		{
+			name:  "Reads a size with a decimal unit",
+			value: `"512MB"`,
+			want:  512_000_000,
+		},
+		{
+			name:  "Reads a size with a binary unit",
+			value: `"10GiB"`,
+			want:  10 << 30,
+		},
+		{
+			name:  "Reads a size string without unit",
+			value: `"2048"`,
+			want:  2048,
+		},
+		{
+			name:  "Errors when the unit is not valid",
+			value: `"10XB"`,
+			wantErr: `invalid configuration:
+Worker.MinFreeSpace: "10XB" is not a valid byte size, e.g. "512MB" or "10GiB"`,
+		},
+		{
+			name:  "Errors when the size is negative",
+			value: "-1",
+			wantErr: `invalid configuration:
+Worker.MinFreeSpace: -1 is less than the minimum value (0)`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := fs.NewDir(t, "preprocessing-test",
+				fs.WithFile("preprocessing.toml", fmt.Sprintf(unitsConfig, "minFreeSpace", tc.value)),
+			)
+
+			var c config.Configuration
+			_, _, err := config.Read(&c, tmpDir.Join("preprocessing.toml"))
+			if tc.wantErr != "" {
+				assert.Error(t, err, tc.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, c.Worker.MinFreeSpace, tc.want)
+		})
+	}
+
+	t.Run("Reports the invalid size with the other errors", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := fs.NewDir(t, "preprocessing-test",
+			fs.WithFile("preprocessing.toml", fmt.Sprintf(unitsConfig, "minFreeSpace", `"10XB"`)+"maxConcurrentSessions = 0\n"),
+		)
+
+		var c config.Configuration
+		_, _, err := config.Read(&c, tmpDir.Join("preprocessing.toml"))
+		assert.Error(t, err, `invalid configuration:
+Worker.MinFreeSpace: "10XB" is not a valid byte size, e.g. "512MB" or "10GiB"
+Worker.MaxConcurrentSessions: 0 is less than the minimum value (1)`)
+	})
+
+	t.Run("Formats the size with a binary unit", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, config.ByteSize(10<<30).String(), "10 GiB")
+	})
+}
+
+func TestByteSizeFromEnv(t *testing.T) {
+	t.Run("Reads a size", func(t *testing.T) {
+		t.Setenv("ENDURO_PREPROCESSING_WORKER_MINFREESPACE", "1.5GB")
+
+		tmpDir := fs.NewDir(t, "preprocessing-test", fs.WithFile("preprocessing.toml", testConfig))
+
+		var c config.Configuration
+		_, _, err := config.Read(&c, tmpDir.Join("preprocessing.toml"))
+		assert.NilError(t, err)
+		assert.Equal(t, c.Worker.MinFreeSpace, config.ByteSize(1_500_000_000))
+	})
+
+	t.Run("Errors when the size is not valid", func(t *testing.T) {
+		t.Setenv("ENDURO_PREPROCESSING_WORKER_MINFREESPACE", "lots")
+
+		tmpDir := fs.NewDir(t, "preprocessing-test", fs.WithFile("preprocessing.toml", testConfig))
+
+		var c config.Configuration
+		_, _, err := config.Read(&c, tmpDir.Join("preprocessing.toml"))
+		assert.Error(t, err, `invalid configuration:
+Worker.MinFreeSpace: "lots" is not a valid byte size, e.g. "512MB" or "10GiB"`)
+	})
+}
+
+func TestDuration(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		value   string
+		want    time.Duration
+		wantErr string
+	}{
+		{
+			name:  "Reads a duration",
+			value: `"90s"`,
+			want:  90 * time.Second,
+		},
+		{
+			name:  "Reads a duration with several units",
+			value: `"1h30m"`,
+			want:  90 * time.Minute,
+		},
+		{
+			name:  "Errors when the unit is not valid",
+			value: `"10y"`,
+			wantErr: `invalid configuration:
+Worker.ActivityTimeout: "10y" is not a valid duration, e.g. "30s" or "1h30m"`,
+		},
+		{
+			name:  "Errors when the unit is missing",
+			value: "30",
+			wantErr: `invalid configuration:
+Worker.ActivityTimeout: 30 is not a valid duration, e.g. "30s" or "1h30m"`,
+		},
+		{
+			name:  "Errors when the duration is negative",
+			value: `"-1m"`,
+			wantErr: `invalid configuration:
+Worker.ActivityTimeout: -1m0s is less than the minimum value (0s)`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tmpDir := fs.NewDir(t, "preprocessing-test",
+				fs.WithFile("preprocessing.toml", fmt.Sprintf(unitsConfig, "activityTimeout", tc.value)),
+			)
+
+			var c config.Configuration
+			_, _, err := config.Read(&c, tmpDir.Join("preprocessing.toml"))
+			if tc.wantErr != "" {
+				assert.Error(t, err, tc.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, c.Worker.ActivityTimeout, tc.want)
+		})
+	}
+}
+
+func TestDurationFromEnv(t *testing.T) {
+	t.Run("Reads a duration", func(t *testing.T) {
+		t.Setenv("ENDURO_PREPROCESSING_WORKER_ACTIVITYTIMEOUT", "2m30s")
+
+		tmpDir := fs.NewDir(t, "preprocessing-test", fs.WithFile("preprocessing.toml", testConfig))
+
+		var c config.Configuration
+		_, _, err := config.Read(&c, tmpDir.Join("preprocessing.toml"))
+		assert.NilError(t, err)
+		assert.Equal(t, c.Worker.ActivityTimeout, 150*time.Second)
+	})
+
+	t.Run("Errors when the duration is not valid", func(t *testing.T) {
+		t.Setenv("ENDURO_PREPROCESSING_WORKER_ACTIVITYTIMEOUT", "soon")
+
+		tmpDir := fs.NewDir(t, "preprocessing-test", fs.WithFile("preprocessing.toml", testConfig))
+
+		var c config.Configuration
+		_, _, err := config.Read(&c, tmpDir.Join("preprocessing.toml"))
+		assert.Error(t, err, `invalid configuration:
+Worker.ActivityTimeout: "soon" is not a valid duration, e.g. "30s" or "1h30m"`)
+	})
+}
@@ -0,0 +1,8 @@
+package enums
+
+// ENUM(
+// software
+// organization
+// person
+// ).
+type AgentType string
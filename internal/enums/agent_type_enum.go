@@ -0,0 +1,179 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version: 0.6.0
+// Revision: 919e61c0174b91303753ee3898569a01abb32c97
+// Build Date: 2023-12-18T15:54:43Z
+// Built By: goreleaser
+
+package enums
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// AgentTypeSoftware is a AgentType of type software.
+	AgentTypeSoftware AgentType = "software"
+	// AgentTypeOrganization is a AgentType of type organization.
+	AgentTypeOrganization AgentType = "organization"
+	// AgentTypePerson is a AgentType of type person.
+	AgentTypePerson AgentType = "person"
+)
+
+var ErrInvalidAgentType = fmt.Errorf("not a valid AgentType, try [%s]", strings.Join(_AgentTypeNames, ", "))
+
+var _AgentTypeNames = []string{
+	string(AgentTypeSoftware),
+	string(AgentTypeOrganization),
+	string(AgentTypePerson),
+}
+
+// AgentTypeNames returns a list of possible string values of AgentType.
+func AgentTypeNames() []string {
+	tmp := make([]string, len(_AgentTypeNames))
+	copy(tmp, _AgentTypeNames)
+	return tmp
+}
+
+// String implements the Stringer interface.
+func (x AgentType) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x AgentType) IsValid() bool {
+	_, err := ParseAgentType(string(x))
+	return err == nil
+}
+
+var _AgentTypeValue = map[string]AgentType{
+	"software":     AgentTypeSoftware,
+	"organization": AgentTypeOrganization,
+	"person":       AgentTypePerson,
+}
+
+// ParseAgentType attempts to convert a string to a AgentType.
+func ParseAgentType(name string) (AgentType, error) {
+	if x, ok := _AgentTypeValue[name]; ok {
+		return x, nil
+	}
+	return AgentType(""), fmt.Errorf("%s is %w", name, ErrInvalidAgentType)
+}
+
+func (x AgentType) Ptr() *AgentType {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x AgentType) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *AgentType) UnmarshalText(text []byte) error {
+	tmp, err := ParseAgentType(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+var errAgentTypeNilPtr = errors.New("value pointer is nil") // one per type for package clashes
+
+// Scan implements the Scanner interface.
+func (x *AgentType) Scan(value interface{}) (err error) {
+	if value == nil {
+		*x = AgentType("")
+		return
+	}
+
+	// A wider range of scannable types.
+	// driver.Value values at the top of the list for expediency
+	switch v := value.(type) {
+	case string:
+		*x, err = ParseAgentType(v)
+	case []byte:
+		*x, err = ParseAgentType(string(v))
+	case AgentType:
+		*x = v
+	case *AgentType:
+		if v == nil {
+			return errAgentTypeNilPtr
+		}
+		*x = *v
+	case *string:
+		if v == nil {
+			return errAgentTypeNilPtr
+		}
+		*x, err = ParseAgentType(*v)
+	default:
+		return errors.New("invalid type for AgentType")
+	}
+
+	return
+}
+
+// Value implements the driver Valuer interface.
+func (x AgentType) Value() (driver.Value, error) {
+	return x.String(), nil
+}
+
+// Set implements the Golang flag.Value interface func.
+func (x *AgentType) Set(val string) error {
+	v, err := ParseAgentType(val)
+	*x = v
+	return err
+}
+
+// Get implements the Golang flag.Getter interface func.
+func (x *AgentType) Get() interface{} {
+	return *x
+}
+
+// Type implements the github.com/spf13/pFlag Value interface.
+func (x *AgentType) Type() string {
+	return "AgentType"
+}
+
+// Values implements the entgo.io/ent/schema/field EnumValues interface.
+func (x AgentType) Values() []string {
+	return AgentTypeNames()
+}
+
+// AgentTypeInterfaces returns an interface list of possible values of AgentType.
+func AgentTypeInterfaces() []interface{} {
+	var tmp []interface{}
+	for _, v := range _AgentTypeNames {
+		tmp = append(tmp, v)
+	}
+	return tmp
+}
+
+// ParseAgentTypeWithDefault attempts to convert a string to a ContentType.
+// It returns the default value if name is empty.
+func ParseAgentTypeWithDefault(name string) (AgentType, error) {
+	if name == "" {
+		return _AgentTypeValue[_AgentTypeNames[0]], nil
+	}
+	if x, ok := _AgentTypeValue[name]; ok {
+		return x, nil
+	}
+	return AgentType(""), fmt.Errorf("%s is not a valid AgentType, try [%s]", name, strings.Join(_AgentTypeNames, ", "))
+}
+
+// NormalizeAgentType attempts to parse a and normalize string as content type.
+// It returns the input untouched if name fails to be parsed.
+// Example:
+//
+//	"enUM" will be normalized (if possible) to "Enum"
+func NormalizeAgentType(name string) string {
+	res, err := ParseAgentType(name)
+	if err != nil {
+		return name
+	}
+	return res.String()
+}
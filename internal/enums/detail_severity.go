@@ -0,0 +1,8 @@
+package enums
+
+// ENUM(
+// info
+// warning
+// error
+// ).
+type DetailSeverity string
@@ -0,0 +1,179 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version: 0.6.0
+// Revision: 919e61c0174b91303753ee3898569a01abb32c97
+// Build Date: 2023-12-18T15:54:43Z
+// Built By: goreleaser
+
+package enums
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// DetailSeverityInfo is a DetailSeverity of type info.
+	DetailSeverityInfo DetailSeverity = "info"
+	// DetailSeverityWarning is a DetailSeverity of type warning.
+	DetailSeverityWarning DetailSeverity = "warning"
+	// DetailSeverityError is a DetailSeverity of type error.
+	DetailSeverityError DetailSeverity = "error"
+)
+
+var ErrInvalidDetailSeverity = fmt.Errorf("not a valid DetailSeverity, try [%s]", strings.Join(_DetailSeverityNames, ", "))
+
+var _DetailSeverityNames = []string{
+	string(DetailSeverityInfo),
+	string(DetailSeverityWarning),
+	string(DetailSeverityError),
+}
+
+// DetailSeverityNames returns a list of possible string values of DetailSeverity.
+func DetailSeverityNames() []string {
+	tmp := make([]string, len(_DetailSeverityNames))
+	copy(tmp, _DetailSeverityNames)
+	return tmp
+}
+
+// String implements the Stringer interface.
+func (x DetailSeverity) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x DetailSeverity) IsValid() bool {
+	_, err := ParseDetailSeverity(string(x))
+	return err == nil
+}
+
+var _DetailSeverityValue = map[string]DetailSeverity{
+	"info":    DetailSeverityInfo,
+	"warning": DetailSeverityWarning,
+	"error":   DetailSeverityError,
+}
+
+// ParseDetailSeverity attempts to convert a string to a DetailSeverity.
+func ParseDetailSeverity(name string) (DetailSeverity, error) {
+	if x, ok := _DetailSeverityValue[name]; ok {
+		return x, nil
+	}
+	return DetailSeverity(""), fmt.Errorf("%s is %w", name, ErrInvalidDetailSeverity)
+}
+
+func (x DetailSeverity) Ptr() *DetailSeverity {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x DetailSeverity) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *DetailSeverity) UnmarshalText(text []byte) error {
+	tmp, err := ParseDetailSeverity(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+var errDetailSeverityNilPtr = errors.New("value pointer is nil") // one per type for package clashes
+
+// Scan implements the Scanner interface.
+func (x *DetailSeverity) Scan(value interface{}) (err error) {
+	if value == nil {
+		*x = DetailSeverity("")
+		return
+	}
+
+	// A wider range of scannable types.
+	// driver.Value values at the top of the list for expediency
+	switch v := value.(type) {
+	case string:
+		*x, err = ParseDetailSeverity(v)
+	case []byte:
+		*x, err = ParseDetailSeverity(string(v))
+	case DetailSeverity:
+		*x = v
+	case *DetailSeverity:
+		if v == nil {
+			return errDetailSeverityNilPtr
+		}
+		*x = *v
+	case *string:
+		if v == nil {
+			return errDetailSeverityNilPtr
+		}
+		*x, err = ParseDetailSeverity(*v)
+	default:
+		return errors.New("invalid type for DetailSeverity")
+	}
+
+	return
+}
+
+// Value implements the driver Valuer interface.
+func (x DetailSeverity) Value() (driver.Value, error) {
+	return x.String(), nil
+}
+
+// Set implements the Golang flag.Value interface func.
+func (x *DetailSeverity) Set(val string) error {
+	v, err := ParseDetailSeverity(val)
+	*x = v
+	return err
+}
+
+// Get implements the Golang flag.Getter interface func.
+func (x *DetailSeverity) Get() interface{} {
+	return *x
+}
+
+// Type implements the github.com/spf13/pFlag Value interface.
+func (x *DetailSeverity) Type() string {
+	return "DetailSeverity"
+}
+
+// Values implements the entgo.io/ent/schema/field EnumValues interface.
+func (x DetailSeverity) Values() []string {
+	return DetailSeverityNames()
+}
+
+// DetailSeverityInterfaces returns an interface list of possible values of DetailSeverity.
+func DetailSeverityInterfaces() []interface{} {
+	var tmp []interface{}
+	for _, v := range _DetailSeverityNames {
+		tmp = append(tmp, v)
+	}
+	return tmp
+}
+
+// ParseDetailSeverityWithDefault attempts to convert a string to a ContentType.
+// It returns the default value if name is empty.
+func ParseDetailSeverityWithDefault(name string) (DetailSeverity, error) {
+	if name == "" {
+		return _DetailSeverityValue[_DetailSeverityNames[0]], nil
+	}
+	if x, ok := _DetailSeverityValue[name]; ok {
+		return x, nil
+	}
+	return DetailSeverity(""), fmt.Errorf("%s is not a valid DetailSeverity, try [%s]", name, strings.Join(_DetailSeverityNames, ", "))
+}
+
+// NormalizeDetailSeverity attempts to parse a and normalize string as content type.
+// It returns the input untouched if name fails to be parsed.
+// Example:
+//
+//	"enUM" will be normalized (if possible) to "Enum"
+func NormalizeDetailSeverity(name string) string {
+	res, err := ParseDetailSeverity(name)
+	if err != nil {
+		return name
+	}
+	return res.String()
+}
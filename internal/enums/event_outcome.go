@@ -3,6 +3,7 @@ package enums
 // ENUM(
 // unspecified
 // success
+// warning
 // system failure
 // validation failure
 // ).
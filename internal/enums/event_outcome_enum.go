@@ -18,6 +18,8 @@ const (
 	EventOutcomeUnspecified EventOutcome = "unspecified"
 	// EventOutcomeSuccess is a EventOutcome of type success.
 	EventOutcomeSuccess EventOutcome = "success"
+	// EventOutcomeWarning is a EventOutcome of type warning.
+	EventOutcomeWarning EventOutcome = "warning"
 	// EventOutcomeSystemFailure is a EventOutcome of type system failure.
 	EventOutcomeSystemFailure EventOutcome = "system failure"
 	// EventOutcomeValidationFailure is a EventOutcome of type validation failure.
@@ -29,6 +31,7 @@ var ErrInvalidEventOutcome = fmt.Errorf("not a valid EventOutcome, try [%s]", st
 var _EventOutcomeNames = []string{
 	string(EventOutcomeUnspecified),
 	string(EventOutcomeSuccess),
+	string(EventOutcomeWarning),
 	string(EventOutcomeSystemFailure),
 	string(EventOutcomeValidationFailure),
 }
@@ -55,6 +58,7 @@ func (x EventOutcome) IsValid() bool {
 var _EventOutcomeValue = map[string]EventOutcome{
 	"unspecified":        EventOutcomeUnspecified,
 	"success":            EventOutcomeSuccess,
+	"warning":            EventOutcomeWarning,
 	"system failure":     EventOutcomeSystemFailure,
 	"validation failure": EventOutcomeValidationFailure,
 }
@@ -0,0 +1,16 @@
+package enums
+
+// EventType is the type of a preservation event, based on the PREMIS event
+// type vocabulary (https://id.loc.gov/vocabulary/preservation/eventType).
+//
+// ENUM(
+// unspecified
+// validation
+// fixity check
+// virus check
+// format identification
+// filename change
+// deletion
+// packing
+// ).
+type EventType string
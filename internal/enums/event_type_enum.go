@@ -0,0 +1,199 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version: 0.6.0
+// Revision: 919e61c0174b91303753ee3898569a01abb32c97
+// Build Date: 2023-12-18T15:54:43Z
+// Built By: goreleaser
+
+package enums
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// EventTypeUnspecified is a EventType of type unspecified.
+	EventTypeUnspecified EventType = "unspecified"
+	// EventTypeValidation is a EventType of type validation.
+	EventTypeValidation EventType = "validation"
+	// EventTypeFixityCheck is a EventType of type fixity check.
+	EventTypeFixityCheck EventType = "fixity check"
+	// EventTypeVirusCheck is a EventType of type virus check.
+	EventTypeVirusCheck EventType = "virus check"
+	// EventTypeFormatIdentification is a EventType of type format identification.
+	EventTypeFormatIdentification EventType = "format identification"
+	// EventTypeFilenameChange is a EventType of type filename change.
+	EventTypeFilenameChange EventType = "filename change"
+	// EventTypeDeletion is a EventType of type deletion.
+	EventTypeDeletion EventType = "deletion"
+	// EventTypePacking is a EventType of type packing.
+	EventTypePacking EventType = "packing"
+)
+
+var ErrInvalidEventType = fmt.Errorf("not a valid EventType, try [%s]", strings.Join(_EventTypeNames, ", "))
+
+var _EventTypeNames = []string{
+	string(EventTypeUnspecified),
+	string(EventTypeValidation),
+	string(EventTypeFixityCheck),
+	string(EventTypeVirusCheck),
+	string(EventTypeFormatIdentification),
+	string(EventTypeFilenameChange),
+	string(EventTypeDeletion),
+	string(EventTypePacking),
+}
+
+// EventTypeNames returns a list of possible string values of EventType.
+func EventTypeNames() []string {
+	tmp := make([]string, len(_EventTypeNames))
+	copy(tmp, _EventTypeNames)
+	return tmp
+}
+
+// String implements the Stringer interface.
+func (x EventType) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x EventType) IsValid() bool {
+	_, err := ParseEventType(string(x))
+	return err == nil
+}
+
+var _EventTypeValue = map[string]EventType{
+	"unspecified":           EventTypeUnspecified,
+	"validation":            EventTypeValidation,
+	"fixity check":          EventTypeFixityCheck,
+	"virus check":           EventTypeVirusCheck,
+	"format identification": EventTypeFormatIdentification,
+	"filename change":       EventTypeFilenameChange,
+	"deletion":              EventTypeDeletion,
+	"packing":               EventTypePacking,
+}
+
+// ParseEventType attempts to convert a string to a EventType.
+func ParseEventType(name string) (EventType, error) {
+	if x, ok := _EventTypeValue[name]; ok {
+		return x, nil
+	}
+	return EventType(""), fmt.Errorf("%s is %w", name, ErrInvalidEventType)
+}
+
+func (x EventType) Ptr() *EventType {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x EventType) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *EventType) UnmarshalText(text []byte) error {
+	tmp, err := ParseEventType(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+var errEventTypeNilPtr = errors.New("value pointer is nil") // one per type for package clashes
+
+// Scan implements the Scanner interface.
+func (x *EventType) Scan(value interface{}) (err error) {
+	if value == nil {
+		*x = EventType("")
+		return
+	}
+
+	// A wider range of scannable types.
+	// driver.Value values at the top of the list for expediency
+	switch v := value.(type) {
+	case string:
+		*x, err = ParseEventType(v)
+	case []byte:
+		*x, err = ParseEventType(string(v))
+	case EventType:
+		*x = v
+	case *EventType:
+		if v == nil {
+			return errEventTypeNilPtr
+		}
+		*x = *v
+	case *string:
+		if v == nil {
+			return errEventTypeNilPtr
+		}
+		*x, err = ParseEventType(*v)
+	default:
+		return errors.New("invalid type for EventType")
+	}
+
+	return
+}
+
+// Value implements the driver Valuer interface.
+func (x EventType) Value() (driver.Value, error) {
+	return x.String(), nil
+}
+
+// Set implements the Golang flag.Value interface func.
+func (x *EventType) Set(val string) error {
+	v, err := ParseEventType(val)
+	*x = v
+	return err
+}
+
+// Get implements the Golang flag.Getter interface func.
+func (x *EventType) Get() interface{} {
+	return *x
+}
+
+// Type implements the github.com/spf13/pFlag Value interface.
+func (x *EventType) Type() string {
+	return "EventType"
+}
+
+// Values implements the entgo.io/ent/schema/field EnumValues interface.
+func (x EventType) Values() []string {
+	return EventTypeNames()
+}
+
+// EventTypeInterfaces returns an interface list of possible values of EventType.
+func EventTypeInterfaces() []interface{} {
+	var tmp []interface{}
+	for _, v := range _EventTypeNames {
+		tmp = append(tmp, v)
+	}
+	return tmp
+}
+
+// ParseEventTypeWithDefault attempts to convert a string to a ContentType.
+// It returns the default value if name is empty.
+func ParseEventTypeWithDefault(name string) (EventType, error) {
+	if name == "" {
+		return _EventTypeValue[_EventTypeNames[0]], nil
+	}
+	if x, ok := _EventTypeValue[name]; ok {
+		return x, nil
+	}
+	return EventType(""), fmt.Errorf("%s is not a valid EventType, try [%s]", name, strings.Join(_EventTypeNames, ", "))
+}
+
+// NormalizeEventType attempts to parse a and normalize string as content type.
+// It returns the input untouched if name fails to be parsed.
+// Example:
+//
+//	"enUM" will be normalized (if possible) to "Enum"
+func NormalizeEventType(name string) string {
+	res, err := ParseEventType(name)
+	if err != nil {
+		return name
+	}
+	return res.String()
+}
@@ -0,0 +1,9 @@
+package enums
+
+// ENUM(
+// unspecified
+// bag
+// zip
+// directory
+// ).
+type PackageType string
@@ -0,0 +1,183 @@
+// Code generated by go-enum DO NOT EDIT.
+// Version: 0.6.0
+// Revision: 919e61c0174b91303753ee3898569a01abb32c97
+// Build Date: 2023-12-18T15:54:43Z
+// Built By: goreleaser
+
+package enums
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// PackageTypeUnspecified is a PackageType of type unspecified.
+	PackageTypeUnspecified PackageType = "unspecified"
+	// PackageTypeBag is a PackageType of type bag.
+	PackageTypeBag PackageType = "bag"
+	// PackageTypeZip is a PackageType of type zip.
+	PackageTypeZip PackageType = "zip"
+	// PackageTypeDirectory is a PackageType of type directory.
+	PackageTypeDirectory PackageType = "directory"
+)
+
+var ErrInvalidPackageType = fmt.Errorf("not a valid PackageType, try [%s]", strings.Join(_PackageTypeNames, ", "))
+
+var _PackageTypeNames = []string{
+	string(PackageTypeUnspecified),
+	string(PackageTypeBag),
+	string(PackageTypeZip),
+	string(PackageTypeDirectory),
+}
+
+// PackageTypeNames returns a list of possible string values of PackageType.
+func PackageTypeNames() []string {
+	tmp := make([]string, len(_PackageTypeNames))
+	copy(tmp, _PackageTypeNames)
+	return tmp
+}
+
+// String implements the Stringer interface.
+func (x PackageType) String() string {
+	return string(x)
+}
+
+// IsValid provides a quick way to determine if the typed value is
+// part of the allowed enumerated values
+func (x PackageType) IsValid() bool {
+	_, err := ParsePackageType(string(x))
+	return err == nil
+}
+
+var _PackageTypeValue = map[string]PackageType{
+	"unspecified": PackageTypeUnspecified,
+	"bag":         PackageTypeBag,
+	"zip":         PackageTypeZip,
+	"directory":   PackageTypeDirectory,
+}
+
+// ParsePackageType attempts to convert a string to a PackageType.
+func ParsePackageType(name string) (PackageType, error) {
+	if x, ok := _PackageTypeValue[name]; ok {
+		return x, nil
+	}
+	return PackageType(""), fmt.Errorf("%s is %w", name, ErrInvalidPackageType)
+}
+
+func (x PackageType) Ptr() *PackageType {
+	return &x
+}
+
+// MarshalText implements the text marshaller method.
+func (x PackageType) MarshalText() ([]byte, error) {
+	return []byte(string(x)), nil
+}
+
+// UnmarshalText implements the text unmarshaller method.
+func (x *PackageType) UnmarshalText(text []byte) error {
+	tmp, err := ParsePackageType(string(text))
+	if err != nil {
+		return err
+	}
+	*x = tmp
+	return nil
+}
+
+var errPackageTypeNilPtr = errors.New("value pointer is nil") // one per type for package clashes
+
+// Scan implements the Scanner interface.
+func (x *PackageType) Scan(value interface{}) (err error) {
+	if value == nil {
+		*x = PackageType("")
+		return
+	}
+
+	// A wider range of scannable types.
+	// driver.Value values at the top of the list for expediency
+	switch v := value.(type) {
+	case string:
+		*x, err = ParsePackageType(v)
+	case []byte:
+		*x, err = ParsePackageType(string(v))
+	case PackageType:
+		*x = v
+	case *PackageType:
+		if v == nil {
+			return errPackageTypeNilPtr
+		}
+		*x = *v
+	case *string:
+		if v == nil {
+			return errPackageTypeNilPtr
+		}
+		*x, err = ParsePackageType(*v)
+	default:
+		return errors.New("invalid type for PackageType")
+	}
+
+	return
+}
+
+// Value implements the driver Valuer interface.
+func (x PackageType) Value() (driver.Value, error) {
+	return x.String(), nil
+}
+
+// Set implements the Golang flag.Value interface func.
+func (x *PackageType) Set(val string) error {
+	v, err := ParsePackageType(val)
+	*x = v
+	return err
+}
+
+// Get implements the Golang flag.Getter interface func.
+func (x *PackageType) Get() interface{} {
+	return *x
+}
+
+// Type implements the github.com/spf13/pFlag Value interface.
+func (x *PackageType) Type() string {
+	return "PackageType"
+}
+
+// Values implements the entgo.io/ent/schema/field EnumValues interface.
+func (x PackageType) Values() []string {
+	return PackageTypeNames()
+}
+
+// PackageTypeInterfaces returns an interface list of possible values of PackageType.
+func PackageTypeInterfaces() []interface{} {
+	var tmp []interface{}
+	for _, v := range _PackageTypeNames {
+		tmp = append(tmp, v)
+	}
+	return tmp
+}
+
+// ParsePackageTypeWithDefault attempts to convert a string to a ContentType.
+// It returns the default value if name is empty.
+func ParsePackageTypeWithDefault(name string) (PackageType, error) {
+	if name == "" {
+		return _PackageTypeValue[_PackageTypeNames[0]], nil
+	}
+	if x, ok := _PackageTypeValue[name]; ok {
+		return x, nil
+	}
+	return PackageType(""), fmt.Errorf("%s is not a valid PackageType, try [%s]", name, strings.Join(_PackageTypeNames, ", "))
+}
+
+// NormalizePackageType attempts to parse a and normalize string as content type.
+// It returns the input untouched if name fails to be parsed.
+// Example:
+//
+//	"enUM" will be normalized (if possible) to "Enum"
+func NormalizePackageType(name string) string {
+	res, err := ParsePackageType(name)
+	if err != nil {
+		return name
+	}
+	return res.String()
+}
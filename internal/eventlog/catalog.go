@@ -0,0 +1,72 @@
+package eventlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+)
+
+// Catalog renders the event messages of a locale from text/template
+// templates identified by message keys, e.g. "Bag {{.Path}}" rendered with
+// the {"Path": "transfer"} params.
+type Catalog struct {
+	locale    string
+	templates map[string]*template.Template
+
+	// fallback renders the messages missing from the catalog.
+	fallback *Catalog
+}
+
+// NewCatalog returns a catalog of locale with the given message key
+// templates. The fallback catalog, if not nil, renders the messages that are
+// missing from templates or fail to render.
+func NewCatalog(locale string, templates map[string]string, fallback *Catalog) (*Catalog, error) {
+	c := &Catalog{
+		locale:    locale,
+		templates: make(map[string]*template.Template, len(templates)),
+		fallback:  fallback,
+	}
+
+	for key, text := range templates {
+		t, err := template.New(key).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("eventlog: catalog %q: %v", locale, err)
+		}
+		c.templates[key] = t
+	}
+
+	return c, nil
+}
+
+// ReadCatalog returns a catalog of locale from a JSON object mapping message
+// keys to templates.
+func ReadCatalog(locale string, data []byte, fallback *Catalog) (*Catalog, error) {
+	var templates map[string]string
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return nil, fmt.Errorf("eventlog: catalog %q: %v", locale, err)
+	}
+
+	return NewCatalog(locale, templates, fallback)
+}
+
+func (c *Catalog) Locale() string {
+	return c.locale
+}
+
+// Render returns the message identified by key rendered with params. It
+// returns the key if neither the catalog nor its fallback can render it.
+func (c *Catalog) Render(key string, params map[string]string) string {
+	if c == nil {
+		return key
+	}
+
+	if t, ok := c.templates[key]; ok {
+		var buf bytes.Buffer
+		if err := t.Execute(&buf, params); err == nil {
+			return buf.String()
+		}
+	}
+
+	return c.fallback.Render(key, params)
+}
@@ -0,0 +1,115 @@
+package eventlog_test
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/enums"
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
+)
+
+func TestCatalog(t *testing.T) {
+	t.Parallel()
+
+	en, err := eventlog.NewCatalog("en", map[string]string{
+		"removed":   "Removed {{.Count}} files from {{.Path}}",
+		"validated": "SIP is valid",
+	}, nil)
+	assert.NilError(t, err)
+
+	fr, err := eventlog.ReadCatalog("fr", []byte(`{"removed": "{{.Count}} fichiers supprimés de {{.Path}}"}`), en)
+	assert.NilError(t, err)
+	assert.Equal(t, fr.Locale(), "fr")
+
+	for _, tc := range []struct {
+		name    string
+		catalog *eventlog.Catalog
+		key     string
+		params  map[string]string
+		want    string
+	}{
+		{
+			name:    "Renders a message with params",
+			catalog: fr,
+			key:     "removed",
+			params:  map[string]string{"Count": "2", "Path": "objects"},
+			want:    "2 fichiers supprimés de objects",
+		},
+		{
+			name:    "Renders a missing message with the fallback catalog",
+			catalog: fr,
+			key:     "validated",
+			want:    "SIP is valid",
+		},
+		{
+			name:    "Renders a message missing params with the fallback catalog",
+			catalog: fr,
+			key:     "removed",
+			params:  map[string]string{"Count": "2"},
+			want:    "removed",
+		},
+		{
+			name:    "Renders an unknown message as its key",
+			catalog: fr,
+			key:     "unknown",
+			want:    "unknown",
+		},
+		{
+			name: "Renders a message as its key without catalog",
+			key:  "validated",
+			want: "validated",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.catalog.Render(tc.key, tc.params), tc.want)
+		})
+	}
+
+	t.Run("Errors if a template is not valid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := eventlog.NewCatalog("de", map[string]string{"removed": "{{.Count"}, nil)
+		assert.ErrorContains(t, err, `eventlog: catalog "de": template: removed:1: unclosed action`)
+	})
+
+	t.Run("Errors if the catalog is not valid JSON", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := eventlog.ReadCatalog("de", []byte(`["removed"]`), nil)
+		assert.ErrorContains(t, err, `eventlog: catalog "de": json: cannot unmarshal array`)
+	})
+}
+
+func TestEventCompleteWithMessage(t *testing.T) {
+	t.Parallel()
+
+	var (
+		started   = time.Date(2024, 6, 6, 14, 48, 12, 0, time.UTC)
+		completed = time.Date(2024, 6, 6, 14, 48, 13, 0, time.UTC)
+	)
+
+	c, err := eventlog.NewCatalog("en", map[string]string{"removed": "Removed {{.Count}} files"}, nil)
+	assert.NilError(t, err)
+
+	event := eventlog.NewEvent(started, enums.EventTypeDeletion, "test event").CompleteWithMessage(
+		completed,
+		enums.EventOutcomeSuccess,
+		c,
+		"removed",
+		map[string]string{"Count": "2"},
+	)
+	assert.DeepEqual(t, event, &eventlog.Event{
+		Name:          "test event",
+		Type:          enums.EventTypeDeletion,
+		Message:       "Removed 2 files",
+		MessageKey:    "removed",
+		MessageParams: map[string]string{"Count": "2"},
+		Outcome:       enums.EventOutcomeSuccess,
+		StartedAt:     started,
+		CompletedAt:   completed,
+	})
+}
@@ -8,21 +8,96 @@ import (
 )
 
 type Event struct {
-	Name        string
-	Message     string
+	Name    string
+	Type    enums.EventType
+	Message string
+
+	// MessageKey and MessageParams identify the Catalog message, they are
+	// kept to render the message in other locales.
+	MessageKey    string            `json:",omitempty"`
+	MessageParams map[string]string `json:",omitempty"`
+
 	Outcome     enums.EventOutcome
 	StartedAt   time.Time
 	CompletedAt time.Time
+	Details     []Detail
+	Agents      []Agent
+	Objects     []Object
+}
+
+// Detail is a structured finding of an event, e.g. a file that failed
+// validation.
+type Detail struct {
+	Severity enums.DetailSeverity
+	Path     string
+	Code     string
+	Message  string
+}
+
+// Agent is a software, organization or person involved in an event.
+type Agent struct {
+	Type enums.AgentType
+	Name string
+
+	// Version is the version of a software agent.
+	Version string
+}
+
+// Object is a file affected by an event, e.g. a file that has been renamed,
+// removed or that failed validation.
+type Object struct {
+	// RelativePath is the path of the object relative to the SIP root.
+	RelativePath string
+
+	// UUID identifies the object, it's empty if the object has not been
+	// assigned an identifier.
+	UUID string `json:",omitempty"`
+
+	// ChecksumAlgorithm and Checksum are the object fixity, they are empty if
+	// the checksum is unknown.
+	ChecksumAlgorithm string `json:",omitempty"`
+	Checksum          string `json:",omitempty"`
+}
+
+// SoftwareAgent returns a software agent with the given name and version.
+func SoftwareAgent(name, version string) Agent {
+	return Agent{Type: enums.AgentTypeSoftware, Name: name, Version: version}
+}
+
+// OrganizationAgent returns an organization agent with the given name.
+func OrganizationAgent(name string) Agent {
+	return Agent{Type: enums.AgentTypeOrganization, Name: name}
 }
 
-func NewEvent(t time.Time, name string) *Event {
+// PersonAgent returns a person agent with the given name, e.g. the operator
+// of a manual step.
+func PersonAgent(name string) Agent {
+	return Agent{Type: enums.AgentTypePerson, Name: name}
+}
+
+func NewEvent(t time.Time, eventType enums.EventType, name string) *Event {
 	return &Event{
 		Name:      name,
+		Type:      eventType,
 		Outcome:   enums.EventOutcomeUnspecified,
 		StartedAt: t,
 	}
 }
 
+// AddAgents adds the given agents to the event.
+func (e *Event) AddAgents(agents ...Agent) *Event {
+	e.Agents = append(e.Agents, agents...)
+
+	return e
+}
+
+// AddObjects links the given objects to the event.
+func (e *Event) AddObjects(objects ...Object) *Event {
+	e.Objects = append(e.Objects, objects...)
+
+	return e
+}
+
 func (e *Event) Complete(t time.Time, outcome enums.EventOutcome, msg string, a ...any) *Event {
 	e.CompletedAt = t
 	e.Outcome = outcome
@@ -31,10 +106,61 @@ func (e *Event) Complete(t time.Time, outcome enums.EventOutcome, msg string, a
 	return e
 }
 
+// CompleteWithMessage completes the event with the message identified by key,
+// rendered by c with params.
+func (e *Event) CompleteWithMessage(
+	t time.Time,
+	outcome enums.EventOutcome,
+	c *Catalog,
+	key string,
+	params map[string]string,
+) *Event {
+	e.MessageKey = key
+	e.MessageParams = params
+
+	return e.Complete(t, outcome, "%s", c.Render(key, params))
+}
+
+// CompleteWithDetails completes the event adding the given details.
+func (e *Event) CompleteWithDetails(
+	t time.Time,
+	outcome enums.EventOutcome,
+	details []Detail,
+	msg string,
+	a ...any,
+) *Event {
+	e.Details = append(e.Details, details...)
+
+	return e.Complete(t, outcome, msg, a...)
+}
+
 func (e *Event) Succeed(t time.Time, msg string, a ...any) *Event {
 	return e.Complete(t, enums.EventOutcomeSuccess, msg, a...)
 }
 
+// Warn completes the event successfully but with issues that need attention.
+func (e *Event) Warn(t time.Time, msg string, a ...any) *Event {
+	return e.Complete(t, enums.EventOutcomeWarning, msg, a...)
+}
+
+// Fail completes the event with a validation failure, the transfer content
+// doesn't meet the preprocessing requirements.
+func (e *Event) Fail(t time.Time, msg string, a ...any) *Event {
+	return e.Complete(t, enums.EventOutcomeValidationFailure, msg, a...)
+}
+
+// SystemFail completes the event with a system failure, the event couldn't be
+// completed due to a system error.
+func (e *Event) SystemFail(t time.Time, msg string, a ...any) *Event {
+	return e.Complete(t, enums.EventOutcomeSystemFailure, msg, a...)
+}
+
+// SucceedWithDetails completes the event successfully adding the given
+// details.
+func (e *Event) SucceedWithDetails(t time.Time, details []Detail, msg string, a ...any) *Event {
+	return e.CompleteWithDetails(t, enums.EventOutcomeSuccess, details, msg, a...)
+}
+
 func (e *Event) IsSuccess() bool {
 	return e.Outcome == enums.EventOutcomeSuccess
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	temporalsdk_converter "go.temporal.io/sdk/converter"
 	"gotest.tools/v3/assert"
 
 	"github.com/artefactual-sdps/preprocessing-base/internal/enums"
@@ -22,7 +23,7 @@ func TestEvent(t *testing.T) {
 	t.Run("Event succeeds", func(t *testing.T) {
 		t.Parallel()
 
-		event := eventlog.NewEvent(started, "test event")
+		event := eventlog.NewEvent(started, enums.EventTypeValidation, "test event")
 		event.Complete(
 			completed,
 			enums.EventOutcomeSuccess,
@@ -31,6 +32,7 @@ func TestEvent(t *testing.T) {
 		)
 		assert.DeepEqual(t, event, &eventlog.Event{
 			Name:        "test event",
+			Type:        enums.EventTypeValidation,
 			Message:     "completed at 2024-06-06T14:48:13Z",
 			Outcome:     enums.EventOutcomeSuccess,
 			StartedAt:   started,
@@ -44,7 +46,7 @@ func TestEvent(t *testing.T) {
 
 		p := "/tmp/test-sip/additional/UpdatedAreldaMetadata.xml"
 
-		event := eventlog.NewEvent(started, "test event")
+		event := eventlog.NewEvent(started, enums.EventTypeValidation, "test event")
 		event.Complete(
 			completed,
 			enums.EventOutcomeValidationFailure,
@@ -53,6 +55,7 @@ func TestEvent(t *testing.T) {
 		)
 		assert.DeepEqual(t, event, &eventlog.Event{
 			Name: "test event",
+			Type: enums.EventTypeValidation,
 			Message: fmt.Sprintf(
 				"Content error: metadata validation has failed: %s does not match expected metadata requirements",
 				p,
@@ -63,4 +66,232 @@ func TestEvent(t *testing.T) {
 		})
 		assert.Equal(t, event.IsSuccess(), false)
 	})
+	t.Run("Event completes with details", func(t *testing.T) {
+		t.Parallel()
+
+		details := []eventlog.Detail{
+			{
+				Severity: enums.DetailSeverityError,
+				Path:     "objects/file1.txt",
+				Code:     "checksum-mismatch",
+				Message:  "checksum does not match the manifest",
+			},
+			{
+				Severity: enums.DetailSeverityWarning,
+				Path:     "objects/file2.txt",
+				Code:     "empty-file",
+				Message:  "file is empty",
+			},
+		}
+
+		event := eventlog.NewEvent(started, enums.EventTypeValidation, "test event")
+		event.CompleteWithDetails(
+			completed,
+			enums.EventOutcomeValidationFailure,
+			details,
+			"Content error: %d files failed validation",
+			1,
+		)
+		assert.DeepEqual(t, event, &eventlog.Event{
+			Name:        "test event",
+			Type:        enums.EventTypeValidation,
+			Message:     "Content error: 1 files failed validation",
+			Outcome:     enums.EventOutcomeValidationFailure,
+			StartedAt:   started,
+			CompletedAt: completed,
+			Details:     details,
+		})
+		assert.Equal(t, event.IsSuccess(), false)
+	})
+
+	t.Run("Event succeeds with details", func(t *testing.T) {
+		t.Parallel()
+
+		details := []eventlog.Detail{
+			{
+				Severity: enums.DetailSeverityInfo,
+				Path:     "objects/Thumbs.db",
+				Code:     "removed",
+				Message:  "file has been removed",
+			},
+		}
+
+		event := eventlog.NewEvent(started, enums.EventTypeValidation, "test event")
+		event.SucceedWithDetails(completed, details, "removed %d files", 1)
+		assert.DeepEqual(t, event, &eventlog.Event{
+			Name:        "test event",
+			Type:        enums.EventTypeValidation,
+			Message:     "removed 1 files",
+			Outcome:     enums.EventOutcomeSuccess,
+			StartedAt:   started,
+			CompletedAt: completed,
+			Details:     details,
+		})
+		assert.Equal(t, event.IsSuccess(), true)
+	})
+}
+
+func TestEventOutcomes(t *testing.T) {
+	t.Parallel()
+
+	var (
+		started   = time.Date(2024, 6, 6, 14, 48, 12, 0, time.UTC)
+		completed = time.Date(2024, 6, 6, 14, 48, 13, 0, time.UTC)
+	)
+
+	for _, tc := range []struct {
+		name     string
+		complete func(*eventlog.Event) *eventlog.Event
+		want     enums.EventOutcome
+	}{
+		{
+			name: "Succeed",
+			complete: func(e *eventlog.Event) *eventlog.Event {
+				return e.Succeed(completed, "event %s", "message")
+			},
+			want: enums.EventOutcomeSuccess,
+		},
+		{
+			name: "Warn",
+			complete: func(e *eventlog.Event) *eventlog.Event {
+				return e.Warn(completed, "event %s", "message")
+			},
+			want: enums.EventOutcomeWarning,
+		},
+		{
+			name: "Fail",
+			complete: func(e *eventlog.Event) *eventlog.Event {
+				return e.Fail(completed, "event %s", "message")
+			},
+			want: enums.EventOutcomeValidationFailure,
+		},
+		{
+			name: "SystemFail",
+			complete: func(e *eventlog.Event) *eventlog.Event {
+				return e.SystemFail(completed, "event %s", "message")
+			},
+			want: enums.EventOutcomeSystemFailure,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			event := tc.complete(eventlog.NewEvent(started, enums.EventTypeValidation, "test event"))
+			assert.DeepEqual(t, event, &eventlog.Event{
+				Name:        "test event",
+				Type:        enums.EventTypeValidation,
+				Message:     "event message",
+				Outcome:     tc.want,
+				StartedAt:   started,
+				CompletedAt: completed,
+			})
+			assert.Equal(t, event.IsSuccess(), tc.want == enums.EventOutcomeSuccess)
+		})
+	}
+}
+
+func TestEventAgents(t *testing.T) {
+	t.Parallel()
+
+	started := time.Date(2024, 6, 6, 14, 48, 12, 0, time.UTC)
+
+	event := eventlog.NewEvent(started, enums.EventTypeValidation, "test event").AddAgents(
+		eventlog.SoftwareAgent("preprocessing-worker", "v1.0.0"),
+		eventlog.OrganizationAgent("Artefactual Systems"),
+	)
+	event.AddAgents(eventlog.PersonAgent("Jane Doe"))
+
+	assert.DeepEqual(t, event.Agents, []eventlog.Agent{
+		{Type: enums.AgentTypeSoftware, Name: "preprocessing-worker", Version: "v1.0.0"},
+		{Type: enums.AgentTypeOrganization, Name: "Artefactual Systems"},
+		{Type: enums.AgentTypePerson, Name: "Jane Doe"},
+	})
+}
+
+func TestEventObjects(t *testing.T) {
+	t.Parallel()
+
+	started := time.Date(2024, 6, 6, 14, 48, 12, 0, time.UTC)
+
+	event := eventlog.NewEvent(started, enums.EventTypeFilenameChange, "test event").AddObjects(
+		eventlog.Object{
+			RelativePath:      "objects/file 1.txt",
+			UUID:              "4b6d1d4c-0d6e-4f3a-9d33-0e4d4c3c1c6f",
+			ChecksumAlgorithm: "sha256",
+			Checksum:          "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+	)
+	event.AddObjects(eventlog.Object{RelativePath: "objects/file_2.txt"})
+
+	assert.DeepEqual(t, event.Objects, []eventlog.Object{
+		{
+			RelativePath:      "objects/file 1.txt",
+			UUID:              "4b6d1d4c-0d6e-4f3a-9d33-0e4d4c3c1c6f",
+			ChecksumAlgorithm: "sha256",
+			Checksum:          "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		{RelativePath: "objects/file_2.txt"},
+	})
+}
+
+func TestEventJSON(t *testing.T) {
+	t.Parallel()
+
+	var (
+		started   = time.Date(2024, 6, 6, 14, 48, 12, 0, time.UTC)
+		completed = time.Date(2024, 6, 6, 14, 48, 13, 0, time.UTC)
+	)
+
+	for _, tc := range []struct {
+		name  string
+		event *eventlog.Event
+	}{
+		{
+			name:  "Round-trips an event without details",
+			event: eventlog.NewEvent(started, enums.EventTypeValidation, "test event").Succeed(completed, "done"),
+		},
+		{
+			name: "Round-trips an event with agents",
+			event: eventlog.NewEvent(started, enums.EventTypeValidation, "test event").AddAgents(
+				eventlog.SoftwareAgent("preprocessing-worker", "v1.0.0"),
+				eventlog.OrganizationAgent("Artefactual Systems"),
+			).Succeed(completed, "done"),
+		},
+		{
+			name: "Round-trips an event with objects",
+			event: eventlog.NewEvent(started, enums.EventTypeDeletion, "test event").AddObjects(
+				eventlog.Object{RelativePath: "objects/Thumbs.db", ChecksumAlgorithm: "md5", Checksum: "abc"},
+				eventlog.Object{RelativePath: "objects/.DS_Store"},
+			).Succeed(completed, "removed 2 files"),
+		},
+		{
+			name: "Round-trips an event with details",
+			event: eventlog.NewEvent(started, enums.EventTypeValidation, "test event").CompleteWithDetails(
+				completed,
+				enums.EventOutcomeValidationFailure,
+				[]eventlog.Detail{
+					{
+						Severity: enums.DetailSeverityError,
+						Path:     "objects/file1.txt",
+						Code:     "invalid-format",
+						Message:  "format is not allowed",
+					},
+				},
+				"Content error: validation has failed",
+			),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			dc := temporalsdk_converter.GetDefaultDataConverter()
+			payload, err := dc.ToPayload(tc.event)
+			assert.NilError(t, err)
+
+			var got eventlog.Event
+			err = dc.FromPayload(payload, &got)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, &got, tc.event)
+		})
+	}
 }
@@ -0,0 +1,34 @@
+// Package eventlogtest provides utilities to test the eventlog sinks.
+package eventlogtest
+
+import (
+	temporalsdk_workflow "go.temporal.io/sdk/workflow"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
+)
+
+// MemorySink keeps the started events in memory, in the order they started.
+type MemorySink struct {
+	events []*eventlog.Event
+}
+
+var _ eventlog.Sink = (*MemorySink)(nil)
+
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+func (s *MemorySink) Started(ctx temporalsdk_workflow.Context, r *eventlog.Record) error {
+	s.events = append(s.events, r.Event)
+
+	return nil
+}
+
+func (s *MemorySink) Completed(ctx temporalsdk_workflow.Context, r *eventlog.Record) error {
+	return nil
+}
+
+// Events returns the started events, completed events are updated in place.
+func (s *MemorySink) Events() []*eventlog.Event {
+	return s.events
+}
@@ -0,0 +1,84 @@
+package eventlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"go.artefactual.dev/tools/temporal"
+)
+
+const (
+	AppendActivityName = "eventlog-append"
+
+	dirMode  fs.FileMode = 0o700
+	fileMode fs.FileMode = 0o600
+)
+
+// Record is a line of the JSON Lines event log. Event records are written as
+// the events complete, and a final record with the Timing report is written
+// when the run ends.
+type Record struct {
+	WorkflowID   string
+	RunID        string
+	RelativePath string
+	Event        *Event  `json:",omitempty"`
+	Timing       *Timing `json:",omitempty"`
+}
+
+type (
+	AppendActivityParams struct {
+		// Path is the path of the JSON Lines file, it's created if it doesn't
+		// exist.
+		Path string
+
+		// Records are appended to the file, one per line.
+		Records []*Record
+	}
+	AppendActivityResult struct{}
+	AppendActivity       struct{}
+)
+
+func NewAppendActivity() *AppendActivity {
+	return &AppendActivity{}
+}
+
+// Execute appends params.Records to the JSON Lines file at params.Path. All
+// the records are written with a single write call so the lines of concurrent
+// workflows are not interleaved.
+func (a *AppendActivity) Execute(ctx context.Context, params *AppendActivityParams) (*AppendActivityResult, error) {
+	logger := temporal.GetLogger(ctx)
+	logger.V(1).Info("Executing eventlog-append activity", "Path", params.Path, "Records", len(params.Records))
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range params.Records {
+		if err := enc.Encode(r); err != nil {
+			return nil, fmt.Errorf("eventlog: encode record: %v", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(params.Path), dirMode); err != nil {
+		return nil, fmt.Errorf("eventlog: %v", err)
+	}
+
+	f, err := os.OpenFile(params.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, fileMode) // #nosec G304 -- trusted path.
+	if err != nil {
+		return nil, fmt.Errorf("eventlog: %v", err)
+	}
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("eventlog: %v", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("eventlog: %v", err)
+	}
+
+	return &AppendActivityResult{}, nil
+}
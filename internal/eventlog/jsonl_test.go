@@ -0,0 +1,75 @@
+package eventlog_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	temporalsdk_activity "go.temporal.io/sdk/activity"
+	temporalsdk_testsuite "go.temporal.io/sdk/testsuite"
+	"gotest.tools/v3/assert"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/enums"
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
+)
+
+func TestAppendActivity(t *testing.T) {
+	t.Parallel()
+
+	var (
+		started   = time.Date(2024, 6, 6, 14, 48, 12, 0, time.UTC)
+		completed = time.Date(2024, 6, 6, 14, 48, 13, 0, time.UTC)
+	)
+
+	record := func(name string) *eventlog.Record {
+		return &eventlog.Record{
+			WorkflowID:   "workflow-id",
+			RunID:        "run-id",
+			RelativePath: "transfer",
+			Event: eventlog.NewEvent(started, enums.EventTypeValidation, name).Succeed(
+				completed,
+				"%s has succeeded",
+				name,
+			),
+		}
+	}
+
+	ts := &temporalsdk_testsuite.WorkflowTestSuite{}
+	env := ts.NewTestActivityEnvironment()
+	env.RegisterActivityWithOptions(
+		eventlog.NewAppendActivity().Execute,
+		temporalsdk_activity.RegisterOptions{Name: eventlog.AppendActivityName},
+	)
+
+	path := filepath.Join(t.TempDir(), "logs", "preprocessing-events.jsonl")
+	want := []*eventlog.Record{record("event 1"), record("event 2"), record("event 3")}
+
+	// Create the file with the first two records and append the last one.
+	_, err := env.ExecuteActivity(eventlog.AppendActivityName, &eventlog.AppendActivityParams{
+		Path:    path,
+		Records: want[:2],
+	})
+	assert.NilError(t, err)
+	_, err = env.ExecuteActivity(eventlog.AppendActivityName, &eventlog.AppendActivityParams{
+		Path:    path,
+		Records: want[2:],
+	})
+	assert.NilError(t, err)
+
+	f, err := os.Open(path)
+	assert.NilError(t, err)
+	defer f.Close()
+
+	var got []*eventlog.Record
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r eventlog.Record
+		assert.NilError(t, json.Unmarshal(scanner.Bytes(), &r))
+		got = append(got, &r)
+	}
+	assert.NilError(t, scanner.Err())
+	assert.DeepEqual(t, got, want)
+}
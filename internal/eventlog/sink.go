@@ -0,0 +1,107 @@
+package eventlog
+
+import (
+	"fmt"
+
+	temporalsdk_workflow "go.temporal.io/sdk/workflow"
+)
+
+// SignalName is the name of the signal sent by SignalSink to the parent
+// workflow, the signal value is a *Record.
+const SignalName = "preprocessing-event"
+
+// Sink receives the preservation events of a workflow as they happen. Sinks
+// run in the workflow code, so they must be deterministic and gate any new
+// workflow command with temporalsdk_workflow.GetVersion.
+type Sink interface {
+	// Started is called when the event of r starts.
+	Started(ctx temporalsdk_workflow.Context, r *Record) error
+
+	// Completed is called when the event of r completes.
+	Completed(ctx temporalsdk_workflow.Context, r *Record) error
+}
+
+// FileSink appends the completed events to a JSON Lines file, executing the
+// AppendActivity.
+type FileSink struct {
+	path string
+}
+
+var _ Sink = (*FileSink)(nil)
+
+// NewFileSink returns a sink that appends the completed events to the JSON
+// Lines file at path.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (s *FileSink) Started(ctx temporalsdk_workflow.Context, r *Record) error {
+	return nil
+}
+
+func (s *FileSink) Completed(ctx temporalsdk_workflow.Context, r *Record) error {
+	// Workflows started before the event log was added can't execute the
+	// activity when they are replayed.
+	v := temporalsdk_workflow.GetVersion(ctx, "eventlog-file", temporalsdk_workflow.DefaultVersion, 1)
+	if v == temporalsdk_workflow.DefaultVersion {
+		return nil
+	}
+
+	return s.Append(ctx, []*Record{r})
+}
+
+// Append appends records to the JSON Lines file. The ctx activity options are
+// used to execute the AppendActivity, callers must gate it with
+// temporalsdk_workflow.GetVersion.
+func (s *FileSink) Append(ctx temporalsdk_workflow.Context, records []*Record) error {
+	return temporalsdk_workflow.ExecuteActivity(
+		ctx,
+		AppendActivityName,
+		&AppendActivityParams{Path: s.path, Records: records},
+	).Get(ctx, nil)
+}
+
+// SignalSink signals the started and completed events to the parent
+// workflow, so it can report them before the child workflow returns. It does
+// nothing when the workflow has no parent.
+type SignalSink struct{}
+
+var _ Sink = (*SignalSink)(nil)
+
+func NewSignalSink() *SignalSink {
+	return &SignalSink{}
+}
+
+func (s *SignalSink) Started(ctx temporalsdk_workflow.Context, r *Record) error {
+	return s.signal(ctx, r)
+}
+
+func (s *SignalSink) Completed(ctx temporalsdk_workflow.Context, r *Record) error {
+	return s.signal(ctx, r)
+}
+
+// Forward signals r, received from a child workflow, to the parent workflow.
+func (s *SignalSink) Forward(ctx temporalsdk_workflow.Context, r *Record) error {
+	return s.signal(ctx, r)
+}
+
+func (s *SignalSink) signal(ctx temporalsdk_workflow.Context, r *Record) error {
+	parent := temporalsdk_workflow.GetInfo(ctx).ParentWorkflowExecution
+	if parent == nil {
+		return nil
+	}
+
+	// Workflows started before the signals were added can't signal the
+	// parent workflow when they are replayed.
+	v := temporalsdk_workflow.GetVersion(ctx, "eventlog-signal", temporalsdk_workflow.DefaultVersion, 1)
+	if v == temporalsdk_workflow.DefaultVersion {
+		return nil
+	}
+
+	err := temporalsdk_workflow.SignalExternalWorkflow(ctx, parent.ID, parent.RunID, SignalName, r).Get(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("eventlog: signal parent workflow: %v", err)
+	}
+
+	return nil
+}
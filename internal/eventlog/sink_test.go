@@ -0,0 +1,90 @@
+package eventlog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	temporalsdk_activity "go.temporal.io/sdk/activity"
+	temporalsdk_testsuite "go.temporal.io/sdk/testsuite"
+	temporalsdk_workflow "go.temporal.io/sdk/workflow"
+	"gotest.tools/v3/assert"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/enums"
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog/eventlogtest"
+)
+
+// emitWorkflow returns a workflow that sends a started and completed event to
+// the given sinks.
+func emitWorkflow(sinks ...eventlog.Sink) func(temporalsdk_workflow.Context) error {
+	return func(ctx temporalsdk_workflow.Context) error {
+		ctx = temporalsdk_workflow.WithActivityOptions(ctx, temporalsdk_workflow.ActivityOptions{
+			StartToCloseTimeout: time.Minute,
+		})
+
+		ev := eventlog.NewEvent(temporalsdk_workflow.Now(ctx), enums.EventTypeValidation, "Validate SIP")
+		r := &eventlog.Record{WorkflowID: "workflow-id", RunID: "run-id", RelativePath: "transfer", Event: ev}
+		for _, s := range sinks {
+			if err := s.Started(ctx, r); err != nil {
+				return err
+			}
+		}
+
+		ev.Succeed(temporalsdk_workflow.Now(ctx), "SIP is valid")
+		for _, s := range sinks {
+			if err := s.Completed(ctx, r); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func TestSinks(t *testing.T) {
+	t.Parallel()
+
+	ts := &temporalsdk_testsuite.WorkflowTestSuite{}
+	env := ts.NewTestWorkflowEnvironment()
+	env.RegisterActivityWithOptions(
+		eventlog.NewAppendActivity().Execute,
+		temporalsdk_activity.RegisterOptions{Name: eventlog.AppendActivityName},
+	)
+
+	want := &eventlog.Event{
+		Name:        "Validate SIP",
+		Type:        enums.EventTypeValidation,
+		Message:     "SIP is valid",
+		Outcome:     enums.EventOutcomeSuccess,
+		StartedAt:   env.Now().UTC(),
+		CompletedAt: env.Now().UTC(),
+	}
+
+	// The file sink only appends the completed event.
+	env.OnActivity(
+		eventlog.AppendActivityName,
+		mock.Anything,
+		&eventlog.AppendActivityParams{
+			Path: "/shared/preprocessing-events.jsonl",
+			Records: []*eventlog.Record{
+				{WorkflowID: "workflow-id", RunID: "run-id", RelativePath: "transfer", Event: want},
+			},
+		},
+	).Return(&eventlog.AppendActivityResult{}, nil).Once()
+
+	mem := eventlogtest.NewMemorySink()
+	env.ExecuteWorkflow(emitWorkflow(
+		mem,
+		eventlog.NewFileSink("/shared/preprocessing-events.jsonl"),
+		// The workflow has no parent, the signal sink does nothing.
+		eventlog.NewSignalSink(),
+	))
+
+	assert.Assert(t, env.IsWorkflowCompleted())
+	assert.NilError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+
+	assert.Equal(t, len(mem.Events()), 1)
+	assert.DeepEqual(t, mem.Events()[0], want)
+}
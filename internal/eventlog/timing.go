@@ -0,0 +1,57 @@
+package eventlog
+
+import "time"
+
+// Timing reports the durations of a preprocessing run and its events.
+type Timing struct {
+	StartedAt   time.Time
+	CompletedAt time.Time
+
+	// WallTime is the total duration of the run.
+	WallTime time.Duration
+
+	Steps []StepTiming
+}
+
+// StepTiming is the duration of an event and, for the events processing the
+// SIP payload files, their throughput.
+type StepTiming struct {
+	Name     string
+	Duration time.Duration
+
+	// Bytes is the number of payload bytes processed, it's zero for events
+	// that don't process the payload files.
+	Bytes int64
+
+	// BytesPerSecond is the event throughput, it's zero when Bytes or
+	// Duration are zero.
+	BytesPerSecond float64
+}
+
+// NewTiming returns the timing report of a run, from startedAt to
+// completedAt, that recorded the given events. The bytes function returns the
+// number of payload bytes processed by each event.
+func NewTiming(startedAt, completedAt time.Time, events []*Event, bytes func(*Event) int64) *Timing {
+	t := &Timing{
+		StartedAt:   startedAt,
+		CompletedAt: completedAt,
+		WallTime:    completedAt.Sub(startedAt),
+		Steps:       make([]StepTiming, 0, len(events)),
+	}
+
+	for _, ev := range events {
+		st := StepTiming{Name: ev.Name}
+		if !ev.CompletedAt.IsZero() {
+			st.Duration = ev.CompletedAt.Sub(ev.StartedAt)
+		}
+		if bytes != nil {
+			st.Bytes = bytes(ev)
+		}
+		if st.Bytes > 0 && st.Duration > 0 {
+			st.BytesPerSecond = float64(st.Bytes) / st.Duration.Seconds()
+		}
+		t.Steps = append(t.Steps, st)
+	}
+
+	return t
+}
@@ -0,0 +1,48 @@
+package eventlog_test
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/enums"
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
+)
+
+func TestNewTiming(t *testing.T) {
+	t.Parallel()
+
+	started := time.Date(2024, 6, 6, 14, 48, 12, 0, time.UTC)
+	validate := eventlog.NewEvent(started, enums.EventTypeValidation, "Validate SIP").Succeed(
+		started.Add(time.Second),
+		"SIP is valid",
+	)
+	bag := eventlog.NewEvent(started.Add(time.Second), enums.EventTypePacking, "Bag SIP").Succeed(
+		started.Add(3*time.Second),
+		"SIP has been bagged",
+	)
+	incomplete := eventlog.NewEvent(started.Add(3*time.Second), enums.EventTypeFixityCheck, "Check fixity")
+
+	got := eventlog.NewTiming(
+		started,
+		started.Add(4*time.Second),
+		[]*eventlog.Event{validate, bag, incomplete},
+		func(ev *eventlog.Event) int64 {
+			if ev == bag {
+				return 1000
+			}
+			return 0
+		},
+	)
+	assert.DeepEqual(t, got, &eventlog.Timing{
+		StartedAt:   started,
+		CompletedAt: started.Add(4 * time.Second),
+		WallTime:    4 * time.Second,
+		Steps: []eventlog.StepTiming{
+			{Name: "Validate SIP", Duration: time.Second},
+			{Name: "Bag SIP", Duration: 2 * time.Second, Bytes: 1000, BytesPerSecond: 500},
+			{Name: "Check fixity"},
+		},
+	})
+}
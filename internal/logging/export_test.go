@@ -0,0 +1,9 @@
+package logging
+
+import "github.com/go-logr/logr"
+
+// NewComponentSink returns the sink of a root logger that switches to the
+// given component sinks.
+func NewComponentSink(root logr.LogSink, components map[string]logr.LogSink) logr.LogSink {
+	return &componentSink{LogSink: root, components: components}
+}
@@ -0,0 +1,127 @@
+// Package logging builds the logger of the worker from the log configuration.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/go-logr/logr"
+	"go.artefactual.dev/tools/log"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+)
+
+// New returns a logger named name that writes the messages to w, or to the
+// log file of cfg.Log if set, and a function that flushes and closes it. The
+// verbosity of the loggers named after cfg.Log.Verbosity components, e.g.
+// logger.WithName("temporal"), is set separately.
+func New(w io.Writer, name string, cfg config.Configuration) (logr.Logger, func(), error) {
+	ws := zapcore.Lock(zapcore.AddSync(w))
+	closeFn := func() {}
+	if cfg.Log.File != "" {
+		if err := checkFile(cfg.Log.File); err != nil {
+			return logr.Discard(), nil, err
+		}
+
+		lj := &lumberjack.Logger{
+			Filename:   cfg.Log.File,
+			MaxSize:    cfg.Log.MaxSize,
+			MaxBackups: cfg.Log.MaxBackups,
+			MaxAge:     cfg.Log.MaxAge,
+			Compress:   cfg.Log.Compress,
+		}
+		ws = zapcore.Lock(zapcore.AddSync(lj))
+		closeFn = func() { _ = lj.Close() }
+	}
+
+	// The debug mode of the log package writes console logs.
+	debug := cfg.Log.Format == "console" || (cfg.Log.Format == "" && cfg.Debug)
+	newLogger := func(verbosity int) logr.Logger {
+		return log.New(ws,
+			log.WithName(name),
+			log.WithDebug(debug),
+			log.WithLevel(verbosity),
+		)
+	}
+
+	root := newLogger(cfg.Verbosity)
+	components := make(map[string]logr.LogSink, len(cfg.Log.Verbosity))
+	for component, verbosity := range cfg.Log.Verbosity {
+		components[component] = newLogger(verbosity).GetSink()
+	}
+	logger := logr.New(&componentSink{LogSink: root.GetSink(), components: components})
+
+	return logger, func() {
+		log.Sync(root)
+		closeFn()
+	}, nil
+}
+
+// checkFile verifies that the log file can be written, creating it and its
+// directory if they don't exist.
+func checkFile(name string) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return fmt.Errorf("log file: %v", err)
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600) // #nosec G304 -- trusted path.
+	if err != nil {
+		return fmt.Errorf("log file: %v", err)
+	}
+
+	return f.Close()
+}
+
+// componentSink is the sink of the root logger, it switches to the sink of a
+// component, with its own verbosity, when a child logger is named after the
+// component.
+type componentSink struct {
+	logr.LogSink
+	components map[string]logr.LogSink
+}
+
+var _ logr.CallDepthLogSink = (*componentSink)(nil)
+
+// Init does nothing, the root and component sinks are initialized by the
+// loggers they come from and initializing them again would skip more frames
+// to find the caller.
+func (s *componentSink) Init(logr.RuntimeInfo) {}
+
+func (s *componentSink) WithName(name string) logr.LogSink {
+	if sink, ok := s.components[name]; ok {
+		return sink.WithName(name)
+	}
+
+	return s.LogSink.WithName(name)
+}
+
+func (s *componentSink) WithValues(keysAndValues ...any) logr.LogSink {
+	components := make(map[string]logr.LogSink, len(s.components))
+	for name, sink := range s.components {
+		components[name] = sink.WithValues(keysAndValues...)
+	}
+
+	return &componentSink{LogSink: s.LogSink.WithValues(keysAndValues...), components: components}
+}
+
+// WithCallDepth adds depth to the call stack frames skipped by the root and
+// component sinks to find the caller of the logger, e.g. in logging helpers.
+func (s *componentSink) WithCallDepth(depth int) logr.LogSink {
+	components := make(map[string]logr.LogSink, len(s.components))
+	for name, sink := range s.components {
+		components[name] = withCallDepth(sink, depth)
+	}
+
+	return &componentSink{LogSink: withCallDepth(s.LogSink, depth), components: components}
+}
+
+func withCallDepth(sink logr.LogSink, depth int) logr.LogSink {
+	if s, ok := sink.(logr.CallDepthLogSink); ok {
+		return s.WithCallDepth(depth)
+	}
+
+	return sink
+}
@@ -0,0 +1,150 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+	"github.com/artefactual-sdps/preprocessing-base/internal/logging"
+)
+
+// messages returns the logger names and messages of the JSON log lines in s.
+func messages(t *testing.T, s string) []string {
+	t.Helper()
+
+	var msgs []string
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		var entry struct {
+			Logger string
+			Msg    string
+		}
+		assert.NilError(t, json.Unmarshal([]byte(line), &entry))
+		msgs = append(msgs, entry.Logger+": "+entry.Msg)
+	}
+
+	return msgs
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Sets the verbosity of the component loggers", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		logger, closeLog, err := logging.New(&out, "preprocessing-worker", config.Configuration{
+			Verbosity: 1,
+			Log: config.LogConfig{
+				Verbosity: map[string]int{"temporal": 0, "worker": 2},
+			},
+		})
+		assert.NilError(t, err)
+
+		logger.V(1).Info("root 1")
+		logger.V(2).Info("root 2")
+		logger.WithName("temporal").V(1).Info("temporal 1")
+		logger.WithName("temporal").Info("temporal 0")
+		logger.WithValues("key", "value").WithName("worker").V(2).Info("worker 2")
+		logger.WithName("replay").V(1).Info("replay 1")
+		closeLog()
+
+		assert.DeepEqual(t, messages(t, out.String()), []string{
+			"preprocessing-worker: root 1",
+			"preprocessing-worker.temporal: temporal 0",
+			"preprocessing-worker.worker: worker 2",
+			"preprocessing-worker.replay: replay 1",
+		})
+	})
+
+	t.Run("Writes console logs", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		logger, closeLog, err := logging.New(&out, "preprocessing-worker", config.Configuration{
+			Log: config.LogConfig{Format: "console"},
+		})
+		assert.NilError(t, err)
+
+		logger.Info("Starting...", "pid", 1)
+		closeLog()
+
+		line := out.String()
+		assert.Assert(t, !json.Valid([]byte(line)), line)
+		assert.Assert(t, strings.Contains(line, "\tINFO\tpreprocessing-worker\t"), line)
+		assert.Assert(t, strings.HasSuffix(line, "\tStarting...\t{\"pid\": 1}\n"), line)
+	})
+
+	t.Run("Reports the caller of the logging helpers", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		newSink := func() logr.LogSink {
+			core := zapcore.NewCore(
+				zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+				zapcore.Lock(zapcore.AddSync(&out)),
+				zapcore.InfoLevel,
+			)
+			return zapr.NewLogger(zap.New(core, zap.AddCaller())).GetSink()
+		}
+		logger := logr.New(logging.NewComponentSink(newSink(), map[string]logr.LogSink{"worker": newSink()}))
+		helper := func(logger logr.Logger) {
+			logger.WithCallDepth(1).Info("helper")
+		}
+
+		_, _, line, _ := runtime.Caller(0)
+		helper(logger)
+		helper(logger.WithName("worker"))
+
+		var callers []string
+		for _, l := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			var entry struct{ Caller string }
+			assert.NilError(t, json.Unmarshal([]byte(l), &entry))
+			callers = append(callers, entry.Caller)
+		}
+		assert.DeepEqual(t, callers, []string{
+			fmt.Sprintf("logging/logging_test.go:%d", line+1),
+			fmt.Sprintf("logging/logging_test.go:%d", line+2),
+		})
+	})
+
+	t.Run("Writes to the log file", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := fs.NewDir(t, "preprocessing-logs")
+		var out bytes.Buffer
+		logger, closeLog, err := logging.New(&out, "preprocessing-worker", config.Configuration{
+			Log: config.LogConfig{File: tmpDir.Join("logs", "worker.log"), MaxSize: 1},
+		})
+		assert.NilError(t, err)
+
+		logger.Info("Starting...")
+		closeLog()
+
+		b, err := os.ReadFile(tmpDir.Join("logs", "worker.log"))
+		assert.NilError(t, err)
+		assert.DeepEqual(t, messages(t, string(b)), []string{"preprocessing-worker: Starting..."})
+		assert.Equal(t, out.Len(), 0)
+	})
+
+	t.Run("Errors when the log file can't be created", func(t *testing.T) {
+		t.Parallel()
+
+		tmpDir := fs.NewDir(t, "preprocessing-logs", fs.WithFile("logs", ""))
+		_, _, err := logging.New(os.Stderr, "preprocessing-worker", config.Configuration{
+			Log: config.LogConfig{File: tmpDir.Join("logs", "worker.log")},
+		})
+		assert.Error(t, err, "log file: mkdir "+tmpDir.Join("logs")+": not a directory")
+	})
+}
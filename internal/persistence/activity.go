@@ -0,0 +1,39 @@
+package persistence
+
+import (
+	"context"
+
+	"go.artefactual.dev/tools/temporal"
+)
+
+const SaveRunActivityName = "persistence-save-run"
+
+type (
+	SaveRunActivityParams struct {
+		Run *Run
+	}
+	SaveRunActivityResult struct{}
+	SaveRunActivity       struct {
+		store *Store
+	}
+)
+
+func NewSaveRunActivity(store *Store) *SaveRunActivity {
+	return &SaveRunActivity{store: store}
+}
+
+// Execute records params.Run and its events in the database.
+func (a *SaveRunActivity) Execute(ctx context.Context, params *SaveRunActivityParams) (*SaveRunActivityResult, error) {
+	logger := temporal.GetLogger(ctx)
+	logger.V(1).Info(
+		"Executing persistence-save-run activity",
+		"RunID", params.Run.RunID,
+		"Events", len(params.Run.Events),
+	)
+
+	if err := a.store.SaveRun(ctx, params.Run); err != nil {
+		return nil, err
+	}
+
+	return &SaveRunActivityResult{}, nil
+}
@@ -0,0 +1,143 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// migrations holds the schema migrations of each driver. The migration files
+// are named "<version>_<description>.sql" and applied in version order.
+//
+//go:embed migrations
+var migrations embed.FS
+
+// migrationLock is the name of the MySQL lock held while the migrations are
+// applied.
+const migrationLock = "preprocessing_schema_migrations"
+
+// migrationLockTimeout is how long a worker waits for the migrations applied
+// by another worker.
+const migrationLockTimeout = time.Minute
+
+// migrate applies the migrations of driver not yet recorded in the
+// schema_migrations table.
+//
+// Each migration and its schema_migrations record are applied in a
+// transaction that checks the record first, so workers starting at the same
+// time don't apply a migration twice. SQLite transactions take the database
+// write lock when they begin (see Open). MySQL commits the schema changes
+// implicitly, the migrations are applied holding a named lock instead and a
+// failed migration may be partially applied.
+func migrate(ctx context.Context, db *sql.DB, driver string) error {
+	dir := path.Join("migrations", driver)
+	entries, err := fs.ReadDir(migrations, dir)
+	if err != nil {
+		return fmt.Errorf("persistence: unsupported driver: %s", driver)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	// Use a single connection, MySQL locks are held by the session.
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("persistence: connect to database: %v", err)
+	}
+	defer conn.Close()
+
+	if driver == "mysql" {
+		unlock, err := lockMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+	}
+
+	if _, err := conn.ExecContext(
+		ctx,
+		"CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL PRIMARY KEY)",
+	); err != nil {
+		return fmt.Errorf("persistence: create schema_migrations table: %v", err)
+	}
+
+	for _, e := range entries {
+		prefix, _, _ := strings.Cut(e.Name(), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return fmt.Errorf("persistence: invalid migration name: %s", e.Name())
+		}
+
+		b, err := fs.ReadFile(migrations, path.Join(dir, e.Name()))
+		if err != nil {
+			return fmt.Errorf("persistence: read migration: %v", err)
+		}
+		if err := applyMigration(ctx, conn, version, string(b)); err != nil {
+			return fmt.Errorf("persistence: apply migration %s: %v", e.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// applyMigration applies the migration query and records its version, unless
+// the version is already recorded.
+func applyMigration(ctx context.Context, conn *sql.Conn, version int, query string) (err error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var n int
+	if err := tx.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM schema_migrations WHERE version = ?",
+		version,
+	).Scan(&n); err != nil {
+		return err
+	}
+	if n > 0 {
+		return tx.Commit()
+	}
+
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES (?)", version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// lockMigrations takes the MySQL migration lock on conn, waiting for the
+// other workers to release it. It returns a function releasing the lock.
+func lockMigrations(ctx context.Context, conn *sql.Conn) (func(), error) {
+	var ok sql.NullInt64
+	if err := conn.QueryRowContext(
+		ctx,
+		"SELECT GET_LOCK(?, ?)",
+		migrationLock,
+		int(migrationLockTimeout.Seconds()),
+	).Scan(&ok); err != nil {
+		return nil, fmt.Errorf("persistence: lock schema migrations: %v", err)
+	}
+	if ok.Int64 != 1 {
+		return nil, errors.New("persistence: lock schema migrations: timeout")
+	}
+
+	return func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), "SELECT RELEASE_LOCK(?)", migrationLock)
+	}, nil
+}
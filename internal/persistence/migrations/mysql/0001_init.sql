@@ -0,0 +1,31 @@
+CREATE TABLE preprocessing_run (
+  run_id VARCHAR(255) NOT NULL PRIMARY KEY,
+  workflow_id VARCHAR(255) NOT NULL,
+  relative_path VARCHAR(4096) NOT NULL,
+  -- outcome is the workflow.Outcome value: 0 success, 1 system error,
+  -- 2 content error and 3 success with warnings.
+  outcome TINYINT NOT NULL,
+  worker_version VARCHAR(255) NOT NULL,
+  started_at DATETIME(6) NOT NULL,
+  completed_at DATETIME(6) NOT NULL,
+  INDEX preprocessing_run_started_at_idx (started_at)
+);
+
+CREATE TABLE preprocessing_event (
+  id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+  run_id VARCHAR(255) NOT NULL,
+  position INT NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  type VARCHAR(64) NOT NULL,
+  message TEXT NOT NULL,
+  outcome VARCHAR(64) NOT NULL,
+  started_at DATETIME(6) NOT NULL,
+  completed_at DATETIME(6) NULL,
+  -- details, agents and objects are JSON arrays.
+  details JSON NOT NULL,
+  agents JSON NOT NULL,
+  objects JSON NOT NULL,
+  INDEX preprocessing_event_run_id_idx (run_id, position),
+  INDEX preprocessing_event_type_idx (type, outcome, started_at),
+  FOREIGN KEY (run_id) REFERENCES preprocessing_run (run_id)
+);
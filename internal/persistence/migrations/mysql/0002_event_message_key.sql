@@ -0,0 +1,4 @@
+-- message_params is a JSON object.
+ALTER TABLE preprocessing_event
+  ADD COLUMN message_key VARCHAR(255) NOT NULL DEFAULT '' AFTER message,
+  ADD COLUMN message_params JSON NULL AFTER message_key;
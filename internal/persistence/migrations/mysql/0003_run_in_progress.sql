@@ -0,0 +1,4 @@
+-- outcome and completed_at are NULL while the run is in progress.
+ALTER TABLE preprocessing_run
+  MODIFY outcome TINYINT NULL,
+  MODIFY completed_at DATETIME(6) NULL;
@@ -0,0 +1,32 @@
+CREATE TABLE preprocessing_run (
+  run_id TEXT NOT NULL PRIMARY KEY,
+  workflow_id TEXT NOT NULL,
+  relative_path TEXT NOT NULL,
+  -- outcome is the workflow.Outcome value: 0 success, 1 system error,
+  -- 2 content error and 3 success with warnings.
+  outcome INTEGER NOT NULL,
+  worker_version TEXT NOT NULL,
+  started_at DATETIME NOT NULL,
+  completed_at DATETIME NOT NULL
+);
+
+CREATE INDEX preprocessing_run_started_at_idx ON preprocessing_run (started_at);
+
+CREATE TABLE preprocessing_event (
+  id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+  run_id TEXT NOT NULL REFERENCES preprocessing_run (run_id),
+  position INTEGER NOT NULL,
+  name TEXT NOT NULL,
+  type TEXT NOT NULL,
+  message TEXT NOT NULL,
+  outcome TEXT NOT NULL,
+  started_at DATETIME NOT NULL,
+  completed_at DATETIME,
+  -- details, agents and objects are JSON arrays.
+  details TEXT NOT NULL,
+  agents TEXT NOT NULL,
+  objects TEXT NOT NULL
+);
+
+CREATE INDEX preprocessing_event_run_id_idx ON preprocessing_event (run_id, position);
+CREATE INDEX preprocessing_event_type_idx ON preprocessing_event (type, outcome, started_at);
@@ -0,0 +1,3 @@
+-- message_params is a JSON object.
+ALTER TABLE preprocessing_event ADD COLUMN message_key TEXT NOT NULL DEFAULT '';
+ALTER TABLE preprocessing_event ADD COLUMN message_params TEXT NOT NULL DEFAULT '{}';
@@ -0,0 +1,21 @@
+-- outcome and completed_at are NULL while the run is in progress. SQLite can't
+-- change the column constraints, copy the runs to a new table.
+CREATE TABLE preprocessing_run_new (
+  run_id TEXT NOT NULL PRIMARY KEY,
+  workflow_id TEXT NOT NULL,
+  relative_path TEXT NOT NULL,
+  outcome INTEGER,
+  worker_version TEXT NOT NULL,
+  started_at DATETIME NOT NULL,
+  completed_at DATETIME
+);
+
+INSERT INTO preprocessing_run_new
+  (run_id, workflow_id, relative_path, outcome, worker_version, started_at, completed_at)
+  SELECT run_id, workflow_id, relative_path, outcome, worker_version, started_at, completed_at
+  FROM preprocessing_run;
+
+DROP TABLE preprocessing_run;
+ALTER TABLE preprocessing_run_new RENAME TO preprocessing_run;
+
+CREATE INDEX preprocessing_run_started_at_idx ON preprocessing_run (started_at);
@@ -0,0 +1,333 @@
+// Package persistence records the preprocessing workflow runs and their
+// preservation events in a SQL database, SQLite or MySQL, for reporting.
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	_ "modernc.org/sqlite"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
+)
+
+// ErrNotFound is returned when a run is not found in the database.
+var ErrNotFound = errors.New("persistence: not found")
+
+// Run is a preprocessing workflow run and its preservation events.
+type Run struct {
+	WorkflowID   string
+	RunID        string
+	RelativePath string
+
+	// Outcome is the workflow.Outcome value of the run, it's not recorded
+	// while the run is in progress.
+	Outcome int
+
+	WorkerVersion string
+	StartedAt     time.Time
+
+	// CompletedAt is zero while the run is in progress.
+	CompletedAt time.Time
+
+	Events []*eventlog.Event
+}
+
+// Store records the preprocessing runs in a SQL database.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the database configured in cfg and applies the pending schema
+// migrations.
+func Open(ctx context.Context, cfg config.DatabaseConfig) (*Store, error) {
+	dsn := cfg.DSN
+	if cfg.Driver == "mysql" {
+		c, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("persistence: parse DSN: %v", err)
+		}
+
+		// Scan DATETIME columns into time.Time values and allow the
+		// migrations to run multiple statements.
+		c.ParseTime = true
+		c.MultiStatements = true
+		dsn = c.FormatDSN()
+	}
+	if cfg.Driver == "sqlite" {
+		// Take the write lock when the transactions begin, waiting for the
+		// transactions of other workers, e.g. applying the migrations.
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		dsn += sep + "_txlock=immediate&_pragma=busy_timeout(60000)"
+	}
+
+	db, err := sql.Open(cfg.Driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("persistence: open database: %v", err)
+	}
+	if cfg.Driver == "sqlite" {
+		// SQLite doesn't allow concurrent writers, serialize the queries.
+		db.SetMaxOpenConns(1)
+	}
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("persistence: connect to database: %v", err)
+	}
+
+	if err := migrate(ctx, db, cfg.Driver); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// SaveRun records r and its events, replacing any previous record of the
+// same run so retries don't duplicate the events. The workflow saves the run
+// when it starts, without events, and again when it completes.
+func (s *Store) SaveRun(ctx context.Context, r *Run) (err error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("persistence: begin transaction: %v", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM preprocessing_event WHERE run_id = ?", r.RunID); err != nil {
+		return fmt.Errorf("persistence: delete events: %v", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM preprocessing_run WHERE run_id = ?", r.RunID); err != nil {
+		return fmt.Errorf("persistence: delete run: %v", err)
+	}
+
+	var (
+		outcome     sql.NullInt64
+		completedAt sql.NullTime
+	)
+	if !r.CompletedAt.IsZero() {
+		outcome = sql.NullInt64{Int64: int64(r.Outcome), Valid: true}
+		completedAt = sql.NullTime{Time: r.CompletedAt.UTC(), Valid: true}
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO preprocessing_run
+		(run_id, workflow_id, relative_path, outcome, worker_version, started_at, completed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		r.RunID,
+		r.WorkflowID,
+		r.RelativePath,
+		outcome,
+		r.WorkerVersion,
+		r.StartedAt.UTC(),
+		completedAt,
+	); err != nil {
+		return fmt.Errorf("persistence: insert run: %v", err)
+	}
+
+	for i, ev := range r.Events {
+		if err := insertEvent(ctx, tx, r.RunID, i, ev); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("persistence: commit transaction: %v", err)
+	}
+
+	return nil
+}
+
+func insertEvent(ctx context.Context, tx *sql.Tx, runID string, position int, ev *eventlog.Event) error {
+	details, err := jsonArray(ev.Details)
+	if err != nil {
+		return err
+	}
+	agents, err := jsonArray(ev.Agents)
+	if err != nil {
+		return err
+	}
+	objects, err := jsonArray(ev.Objects)
+	if err != nil {
+		return err
+	}
+	messageParams, err := jsonObject(ev.MessageParams)
+	if err != nil {
+		return err
+	}
+
+	var completedAt sql.NullTime
+	if !ev.CompletedAt.IsZero() {
+		completedAt = sql.NullTime{Time: ev.CompletedAt.UTC(), Valid: true}
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO preprocessing_event
+		(run_id, position, name, type, message, message_key, message_params, outcome, started_at, completed_at,
+		details, agents, objects)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		runID,
+		position,
+		ev.Name,
+		ev.Type,
+		ev.Message,
+		ev.MessageKey,
+		messageParams,
+		ev.Outcome,
+		ev.StartedAt.UTC(),
+		completedAt,
+		details,
+		agents,
+		objects,
+	); err != nil {
+		return fmt.Errorf("persistence: insert event: %v", err)
+	}
+
+	return nil
+}
+
+// Run returns the run with the given runID and its events.
+func (s *Store) Run(ctx context.Context, runID string) (*Run, error) {
+	var (
+		r           = Run{RunID: runID}
+		outcome     sql.NullInt64
+		completedAt sql.NullTime
+	)
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT workflow_id, relative_path, outcome, worker_version, started_at, completed_at
+		FROM preprocessing_run WHERE run_id = ?`,
+		runID,
+	).Scan(&r.WorkflowID, &r.RelativePath, &outcome, &r.WorkerVersion, &r.StartedAt, &completedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("persistence: select run: %v", err)
+	}
+	r.Outcome = int(outcome.Int64)
+	r.StartedAt = r.StartedAt.UTC()
+	if completedAt.Valid {
+		r.CompletedAt = completedAt.Time.UTC()
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT name, type, message, message_key, message_params, outcome, started_at, completed_at,
+		details, agents, objects
+		FROM preprocessing_event WHERE run_id = ? ORDER BY position`,
+		runID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("persistence: select events: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		ev, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		r.Events = append(r.Events, ev)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("persistence: select events: %v", err)
+	}
+
+	return &r, nil
+}
+
+func scanEvent(rows *sql.Rows) (*eventlog.Event, error) {
+	var (
+		ev                       eventlog.Event
+		completedAt              sql.NullTime
+		messageParams            []byte
+		details, agents, objects []byte
+	)
+	if err := rows.Scan(
+		&ev.Name,
+		&ev.Type,
+		&ev.Message,
+		&ev.MessageKey,
+		&messageParams,
+		&ev.Outcome,
+		&ev.StartedAt,
+		&completedAt,
+		&details,
+		&agents,
+		&objects,
+	); err != nil {
+		return nil, fmt.Errorf("persistence: scan event: %v", err)
+	}
+
+	ev.StartedAt = ev.StartedAt.UTC()
+	if completedAt.Valid {
+		ev.CompletedAt = completedAt.Time.UTC()
+	}
+
+	for _, f := range []struct {
+		data []byte
+		v    any
+	}{
+		{details, &ev.Details},
+		{agents, &ev.Agents},
+		{objects, &ev.Objects},
+		{messageParams, &ev.MessageParams},
+	} {
+		// Keep the values of events without them nil.
+		if len(f.data) == 0 || string(f.data) == "[]" || string(f.data) == "{}" {
+			continue
+		}
+		if err := json.Unmarshal(f.data, f.v); err != nil {
+			return nil, fmt.Errorf("persistence: scan event: %v", err)
+		}
+	}
+
+	return &ev, nil
+}
+
+// jsonObject encodes m as a JSON object, encoding nil maps as empty objects.
+func jsonObject(m map[string]string) (string, error) {
+	if m == nil {
+		return "{}", nil
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("persistence: encode event: %v", err)
+	}
+
+	return string(b), nil
+}
+
+// jsonArray encodes s as a JSON array, encoding nil slices as empty arrays.
+func jsonArray[T any](s []T) (string, error) {
+	if s == nil {
+		return "[]", nil
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("persistence: encode event: %v", err)
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,272 @@
+package persistence_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	temporalsdk_activity "go.temporal.io/sdk/activity"
+	temporalsdk_testsuite "go.temporal.io/sdk/testsuite"
+	"gotest.tools/v3/assert"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+	"github.com/artefactual-sdps/preprocessing-base/internal/enums"
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
+	"github.com/artefactual-sdps/preprocessing-base/internal/persistence"
+)
+
+var (
+	started   = time.Date(2024, 6, 6, 14, 48, 12, 0, time.UTC)
+	completed = time.Date(2024, 6, 6, 14, 48, 13, 0, time.UTC)
+)
+
+func testRun() *persistence.Run {
+	return &persistence.Run{
+		WorkflowID:    "workflow-id",
+		RunID:         "run-id",
+		RelativePath:  "transfer",
+		Outcome:       2,
+		WorkerVersion: "v1.0.0",
+		StartedAt:     started,
+		CompletedAt:   completed,
+		Events: []*eventlog.Event{
+			eventlog.NewEvent(started, enums.EventTypeVirusCheck, "Scan for viruses").AddAgents(
+				eventlog.SoftwareAgent("clamav", "1.3.1"),
+			).AddObjects(
+				eventlog.Object{RelativePath: "objects/eicar.com", ChecksumAlgorithm: "md5", Checksum: "abc"},
+			).CompleteWithDetails(
+				completed,
+				enums.EventOutcomeValidationFailure,
+				[]eventlog.Detail{
+					{
+						Severity: enums.DetailSeverityError,
+						Path:     "objects/eicar.com",
+						Code:     "virus-found",
+						Message:  "Eicar-Test-Signature",
+					},
+				},
+				"Content error: %d files are infected",
+				1,
+			),
+			eventlog.NewEvent(completed, enums.EventTypePacking, "Bag SIP"),
+			eventlog.NewEvent(started, enums.EventTypeValidation, "Validate SIP").CompleteWithMessage(
+				completed,
+				enums.EventOutcomeSuccess,
+				nil,
+				"validate-sip.succeeded",
+				map[string]string{"Path": "transfer"},
+			),
+		},
+	}
+}
+
+func openStore(t *testing.T, path string) *persistence.Store {
+	t.Helper()
+
+	store, err := persistence.Open(context.Background(), config.DatabaseConfig{
+		Enabled: true,
+		Driver:  "sqlite",
+		DSN:     path,
+	})
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	return store
+}
+
+// openConcurrently opens the cfg database from multiple goroutines, like
+// workers starting at the same time, and closes it.
+func openConcurrently(t *testing.T, cfg config.DatabaseConfig) {
+	t.Helper()
+
+	errs := make([]error, 5)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store, err := persistence.Open(context.Background(), cfg)
+			if err == nil {
+				err = store.Close()
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NilError(t, err)
+	}
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Saves a run and its events", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		store := openStore(t, filepath.Join(t.TempDir(), "preprocessing.db"))
+
+		err := store.SaveRun(ctx, testRun())
+		assert.NilError(t, err)
+
+		got, err := store.Run(ctx, "run-id")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, got, testRun())
+	})
+
+	t.Run("Replaces a saved run", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		store := openStore(t, filepath.Join(t.TempDir(), "preprocessing.db"))
+
+		err := store.SaveRun(ctx, testRun())
+		assert.NilError(t, err)
+
+		want := testRun()
+		want.Outcome = 0
+		want.Events = want.Events[1:2]
+		err = store.SaveRun(ctx, want)
+		assert.NilError(t, err)
+
+		got, err := store.Run(ctx, "run-id")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, got, want)
+	})
+
+	t.Run("Saves a run in progress", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		store := openStore(t, filepath.Join(t.TempDir(), "preprocessing.db"))
+
+		want := testRun()
+		want.Outcome = 0
+		want.CompletedAt = time.Time{}
+		want.Events = nil
+		err := store.SaveRun(ctx, want)
+		assert.NilError(t, err)
+
+		got, err := store.Run(ctx, "run-id")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, got, want)
+
+		err = store.SaveRun(ctx, testRun())
+		assert.NilError(t, err)
+
+		got, err = store.Run(ctx, "run-id")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, got, testRun())
+	})
+
+	t.Run("Opens a new database concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "preprocessing.db")
+		openConcurrently(t, config.DatabaseConfig{Enabled: true, Driver: "sqlite", DSN: path})
+
+		store := openStore(t, path)
+		assert.NilError(t, store.SaveRun(context.Background(), testRun()))
+	})
+
+	t.Run("Reopens a migrated database", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		path := filepath.Join(t.TempDir(), "preprocessing.db")
+		store := openStore(t, path)
+		assert.NilError(t, store.SaveRun(ctx, testRun()))
+		assert.NilError(t, store.Close())
+
+		got, err := openStore(t, path).Run(ctx, "run-id")
+		assert.NilError(t, err)
+		assert.DeepEqual(t, got, testRun())
+	})
+
+	t.Run("Errors if the run is not found", func(t *testing.T) {
+		t.Parallel()
+
+		store := openStore(t, filepath.Join(t.TempDir(), "preprocessing.db"))
+
+		_, err := store.Run(context.Background(), "missing")
+		assert.ErrorIs(t, err, persistence.ErrNotFound)
+	})
+
+	t.Run("Errors if the driver is not supported", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := persistence.Open(context.Background(), config.DatabaseConfig{
+			Driver: "postgres",
+			DSN:    "postgres://localhost",
+		})
+		assert.ErrorContains(t, err, "persistence: open database: sql: unknown driver \"postgres\"")
+	})
+}
+
+// TestStoreMySQL runs against the MySQL database of the
+// PREPROCESSING_TEST_MYSQL_DSN environment variable, e.g.
+// "root:root@tcp(localhost:3306)/preprocessing_test", it's skipped if the
+// variable is not set.
+func TestStoreMySQL(t *testing.T) {
+	dsn := os.Getenv("PREPROCESSING_TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("PREPROCESSING_TEST_MYSQL_DSN is not set")
+	}
+
+	ctx := context.Background()
+	cfg := config.DatabaseConfig{Enabled: true, Driver: "mysql", DSN: dsn}
+	openConcurrently(t, cfg)
+
+	store, err := persistence.Open(ctx, cfg)
+	assert.NilError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	// The database may have the runs of previous test runs.
+	want := testRun()
+	want.RunID = fmt.Sprintf("run-id-%d", time.Now().UnixNano())
+
+	started := *want
+	started.Outcome = 0
+	started.CompletedAt = time.Time{}
+	started.Events = nil
+	assert.NilError(t, store.SaveRun(ctx, &started))
+
+	got, err := store.Run(ctx, want.RunID)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, &started)
+
+	assert.NilError(t, store.SaveRun(ctx, want))
+
+	got, err = store.Run(ctx, want.RunID)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, want)
+}
+
+func TestSaveRunActivity(t *testing.T) {
+	t.Parallel()
+
+	store := openStore(t, filepath.Join(t.TempDir(), "preprocessing.db"))
+
+	ts := &temporalsdk_testsuite.WorkflowTestSuite{}
+	env := ts.NewTestActivityEnvironment()
+	env.RegisterActivityWithOptions(
+		persistence.NewSaveRunActivity(store).Execute,
+		temporalsdk_activity.RegisterOptions{Name: persistence.SaveRunActivityName},
+	)
+
+	_, err := env.ExecuteActivity(
+		persistence.SaveRunActivityName,
+		&persistence.SaveRunActivityParams{Run: testRun()},
+	)
+	assert.NilError(t, err)
+
+	got, err := store.Run(context.Background(), "run-id")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, testRun())
+}
@@ -0,0 +1,166 @@
+package workflow
+
+import (
+	"fmt"
+
+	"go.artefactual.dev/tools/temporal"
+	temporalsdk_workflow "go.temporal.io/sdk/workflow"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
+)
+
+type BatchPreprocessingWorkflowParams struct {
+	// RelativePaths are the SharedPath relative paths of the transfers to
+	// preprocess.
+	RelativePaths []string
+
+	// MaxConcurrency limits the number of child workflows running at the same
+	// time. The worker default is used when it's less than 1.
+	MaxConcurrency int
+
+	// DryRun is passed to the child workflows to validate the transfers
+	// without modifying them.
+	DryRun bool
+
+	// Profile is passed to the child workflows to select the configuration
+	// overrides of their transfer policy.
+	Profile string `json:",omitempty"`
+}
+
+type BatchPreprocessingWorkflowResult struct {
+	Outcome Outcome
+	Items   []*BatchItemResult
+}
+
+// BatchItemResult is the outcome of preprocessing one transfer of a batch.
+type BatchItemResult struct {
+	RelativePath string
+
+	// Result is the child workflow result, it's nil when the child workflow
+	// returned an error.
+	Result *PreprocessingWorkflowResult
+
+	// Error is the child workflow error message, if any.
+	Error string
+}
+
+// outcome returns the outcome of the item, an item that failed to return a
+// result is considered a system error.
+func (i *BatchItemResult) outcome() Outcome {
+	if i.Result == nil {
+		return OutcomeSystemError
+	}
+
+	return i.Result.Outcome
+}
+
+// BatchPreprocessingWorkflow fans out a PreprocessingWorkflow child execution
+// for each relative path and aggregates their results. The events signaled by
+// the child workflows are forwarded to the parent workflow, if any.
+type BatchPreprocessingWorkflow struct {
+	workflowName   string
+	maxConcurrency int
+	signals        *eventlog.SignalSink
+}
+
+func NewBatchPreprocessingWorkflow(workflowName string, maxConcurrency int) *BatchPreprocessingWorkflow {
+	return &BatchPreprocessingWorkflow{
+		workflowName:   workflowName,
+		maxConcurrency: maxConcurrency,
+		signals:        eventlog.NewSignalSink(),
+	}
+}
+
+func (w *BatchPreprocessingWorkflow) Execute(
+	ctx temporalsdk_workflow.Context,
+	params *BatchPreprocessingWorkflowParams,
+) (*BatchPreprocessingWorkflowResult, error) {
+	logger := temporalsdk_workflow.GetLogger(ctx)
+	logger.Debug("BatchPreprocessingWorkflow workflow running!", "params", params)
+
+	if params == nil || len(params.RelativePaths) == 0 {
+		return nil, temporal.NewNonRetryableError(fmt.Errorf("error calling workflow with unexpected inputs"))
+	}
+
+	limit := w.maxConcurrency
+	if params.MaxConcurrency > 0 {
+		limit = params.MaxConcurrency
+	}
+	if limit < 1 {
+		limit = 1
+	}
+
+	result := &BatchPreprocessingWorkflowResult{
+		Items: make([]*BatchItemResult, len(params.RelativePaths)),
+	}
+
+	// Drain the events signaled by the child workflows while they run.
+	signals := temporalsdk_workflow.GetSignalChannel(ctx, eventlog.SignalName)
+	forward := func(ctx temporalsdk_workflow.Context, r *eventlog.Record) {
+		if err := w.signals.Forward(ctx, r); err != nil {
+			logger.Warn("Couldn't forward child workflow event", "RelativePath", r.RelativePath, "error", err.Error())
+		}
+	}
+	temporalsdk_workflow.Go(ctx, func(ctx temporalsdk_workflow.Context) {
+		for {
+			var r eventlog.Record
+			if !signals.Receive(ctx, &r) {
+				return
+			}
+			forward(ctx, &r)
+		}
+	})
+
+	selector := temporalsdk_workflow.NewSelector(ctx)
+	pending := 0
+	for i, relPath := range params.RelativePaths {
+		// Wait for a child workflow to finish before starting a new one when
+		// the concurrency limit has been reached.
+		if pending >= limit {
+			selector.Select(ctx)
+			pending--
+		}
+
+		item := &BatchItemResult{RelativePath: relPath}
+		result.Items[i] = item
+
+		future := temporalsdk_workflow.ExecuteChildWorkflow(
+			ctx,
+			w.workflowName,
+			&PreprocessingWorkflowParams{
+				RelativePath: relPath,
+				DryRun:       params.DryRun,
+				Profile:      params.Profile,
+			},
+		)
+		selector.AddFuture(future, func(f temporalsdk_workflow.Future) {
+			var r PreprocessingWorkflowResult
+			if err := f.Get(ctx, &r); err != nil {
+				// Don't abort the batch, record the error and let the
+				// sibling workflows finish.
+				logger.Error("Child workflow failed", "RelativePath", relPath, "error", err.Error())
+				item.Error = err.Error()
+				return
+			}
+			item.Result = &r
+		})
+		pending++
+	}
+
+	for ; pending > 0; pending-- {
+		selector.Select(ctx)
+	}
+
+	// Forward the events received with the last child workflow results.
+	for {
+		var r eventlog.Record
+		if !signals.ReceiveAsync(&r) {
+			break
+		}
+		forward(ctx, &r)
+	}
+
+	result.Outcome = batchOutcome(result.Items)
+
+	return result, nil
+}
@@ -0,0 +1,214 @@
+package workflow_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	temporalsdk_testsuite "go.temporal.io/sdk/testsuite"
+	temporalsdk_workflow "go.temporal.io/sdk/workflow"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
+	"github.com/artefactual-sdps/preprocessing-base/internal/workflow"
+)
+
+const childWorkflowName = "preprocessing"
+
+type BatchPreprocessingTestSuite struct {
+	suite.Suite
+	temporalsdk_testsuite.WorkflowTestSuite
+
+	env      *temporalsdk_testsuite.TestWorkflowEnvironment
+	workflow *workflow.BatchPreprocessingWorkflow
+}
+
+func (s *BatchPreprocessingTestSuite) SetupTest(maxConcurrency int, child any) {
+	s.env = s.NewTestWorkflowEnvironment()
+	s.env.RegisterWorkflowWithOptions(
+		child,
+		temporalsdk_workflow.RegisterOptions{Name: childWorkflowName},
+	)
+
+	s.workflow = workflow.NewBatchPreprocessingWorkflow(childWorkflowName, maxConcurrency)
+}
+
+func (s *BatchPreprocessingTestSuite) AfterTest(suiteName, testName string) {
+	s.env.AssertExpectations(s.T())
+}
+
+func TestBatchPreprocessingWorkflow(t *testing.T) {
+	suite.Run(t, new(BatchPreprocessingTestSuite))
+}
+
+func (s *BatchPreprocessingTestSuite) TestAggregatesResults() {
+	child := func(
+		ctx temporalsdk_workflow.Context,
+		params *workflow.PreprocessingWorkflowParams,
+	) (*workflow.PreprocessingWorkflowResult, error) {
+		switch params.RelativePath {
+		case "transfer2":
+			return nil, errors.New("child workflow failed")
+		case "transfer3":
+			return &workflow.PreprocessingWorkflowResult{
+				Outcome:      workflow.OutcomeContentError,
+				RelativePath: params.RelativePath,
+			}, nil
+		case "transfer4":
+			return &workflow.PreprocessingWorkflowResult{
+				Outcome:      workflow.OutcomeSuccessWithWarnings,
+				RelativePath: params.RelativePath,
+			}, nil
+		default:
+			return &workflow.PreprocessingWorkflowResult{
+				Outcome:      workflow.OutcomeSuccess,
+				RelativePath: params.RelativePath,
+			}, nil
+		}
+	}
+	s.SetupTest(5, child)
+
+	s.env.ExecuteWorkflow(
+		s.workflow.Execute,
+		&workflow.BatchPreprocessingWorkflowParams{
+			RelativePaths: []string{"transfer1", "transfer2", "transfer3", "transfer4"},
+		},
+	)
+
+	s.True(s.env.IsWorkflowCompleted())
+
+	var result workflow.BatchPreprocessingWorkflowResult
+	err := s.env.GetWorkflowResult(&result)
+	s.NoError(err)
+	s.Equal(workflow.OutcomeSystemError, result.Outcome)
+	s.Len(result.Items, 4)
+
+	s.Equal("transfer1", result.Items[0].RelativePath)
+	s.Equal(workflow.OutcomeSuccess, result.Items[0].Result.Outcome)
+	s.Empty(result.Items[0].Error)
+
+	s.Equal("transfer2", result.Items[1].RelativePath)
+	s.Nil(result.Items[1].Result)
+	s.Contains(result.Items[1].Error, "child workflow failed")
+
+	s.Equal("transfer3", result.Items[2].RelativePath)
+	s.Equal(workflow.OutcomeContentError, result.Items[2].Result.Outcome)
+	s.Empty(result.Items[2].Error)
+
+	s.Equal("transfer4", result.Items[3].RelativePath)
+	s.Equal(workflow.OutcomeSuccessWithWarnings, result.Items[3].Result.Outcome)
+	s.Empty(result.Items[3].Error)
+}
+
+func (s *BatchPreprocessingTestSuite) TestLimitsConcurrency() {
+	var running, maxRunning int
+	child := func(
+		ctx temporalsdk_workflow.Context,
+		params *workflow.PreprocessingWorkflowParams,
+	) (*workflow.PreprocessingWorkflowResult, error) {
+		running++
+		maxRunning = max(maxRunning, running)
+		defer func() { running-- }()
+
+		if err := temporalsdk_workflow.Sleep(ctx, time.Minute); err != nil {
+			return nil, err
+		}
+
+		return &workflow.PreprocessingWorkflowResult{RelativePath: params.RelativePath}, nil
+	}
+	s.SetupTest(5, child)
+
+	s.env.ExecuteWorkflow(
+		s.workflow.Execute,
+		&workflow.BatchPreprocessingWorkflowParams{
+			RelativePaths:  []string{"t1", "t2", "t3", "t4", "t5"},
+			MaxConcurrency: 2,
+		},
+	)
+
+	s.True(s.env.IsWorkflowCompleted())
+
+	var result workflow.BatchPreprocessingWorkflowResult
+	err := s.env.GetWorkflowResult(&result)
+	s.NoError(err)
+	s.Equal(workflow.OutcomeSuccess, result.Outcome)
+	s.Len(result.Items, 5)
+	s.Equal(2, maxRunning)
+}
+
+func (s *BatchPreprocessingTestSuite) TestForwardsParamsAndSignals() {
+	var params []*workflow.PreprocessingWorkflowParams
+	child := func(
+		ctx temporalsdk_workflow.Context,
+		p *workflow.PreprocessingWorkflowParams,
+	) (*workflow.PreprocessingWorkflowResult, error) {
+		params = append(params, p)
+		r := &eventlog.Record{RelativePath: p.RelativePath}
+		if err := eventlog.NewSignalSink().Started(ctx, r); err != nil {
+			return nil, err
+		}
+
+		return &workflow.PreprocessingWorkflowResult{RelativePath: p.RelativePath}, nil
+	}
+	s.SetupTest(5, child)
+	s.env.RegisterWorkflowWithOptions(
+		s.workflow.Execute,
+		temporalsdk_workflow.RegisterOptions{Name: "preprocessing-batch"},
+	)
+
+	// The batch workflow forwards the signals of its child workflows to its
+	// own parent workflow.
+	var (
+		result  workflow.BatchPreprocessingWorkflowResult
+		signals []string
+	)
+	s.env.ExecuteWorkflow(func(ctx temporalsdk_workflow.Context) error {
+		err := temporalsdk_workflow.ExecuteChildWorkflow(
+			ctx,
+			"preprocessing-batch",
+			&workflow.BatchPreprocessingWorkflowParams{
+				RelativePaths: []string{"t1", "t2"},
+				DryRun:        true,
+				Profile:       "donor-a",
+			},
+		).Get(ctx, &result)
+		if err != nil {
+			return err
+		}
+
+		ch := temporalsdk_workflow.GetSignalChannel(ctx, eventlog.SignalName)
+		for {
+			var r eventlog.Record
+			if !ch.ReceiveAsync(&r) {
+				return nil
+			}
+			signals = append(signals, r.RelativePath)
+		}
+	})
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+	s.Equal(workflow.OutcomeSuccess, result.Outcome)
+	s.ElementsMatch([]string{"t1", "t2"}, signals)
+
+	s.Len(params, 2)
+	for _, p := range params {
+		s.True(p.DryRun)
+		s.Equal("donor-a", p.Profile)
+	}
+}
+
+func (s *BatchPreprocessingTestSuite) TestInvalidParams() {
+	s.SetupTest(5, workflow.NewPreprocessingWorkflow(
+		config.NewStore(config.Configuration{SharedPath: sharedPath}), nil, nil,
+	).Execute)
+
+	s.env.ExecuteWorkflow(
+		s.workflow.Execute,
+		&workflow.BatchPreprocessingWorkflowParams{},
+	)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.ErrorContains(s.env.GetWorkflowError(), "error calling workflow with unexpected inputs")
+}
@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
+)
+
+// defaultLocale is the locale of the messages rendered when no catalog is
+// configured, its catalog must include all the message keys.
+const defaultLocale = "en"
+
+// Event message keys, their templates are in the messages/<locale>.json
+// catalogs.
+const (
+	msgBagSIPDryRun      = "bag-sip.dry-run"
+	msgBagSIPSucceeded   = "bag-sip.succeeded"
+	msgBagSIPSystemError = "bag-sip.system-error"
+)
+
+//go:embed messages/*.json
+var messages embed.FS
+
+// LoadCatalog returns the message catalog of cfg.Locale. The built-in catalog
+// of the locale, if any, is extended by the catalog file configured for the
+// locale in cfg.Catalogs. Messages missing from the catalogs are rendered in
+// English.
+func LoadCatalog(cfg config.MessagesConfig) (*eventlog.Catalog, error) {
+	c, err := builtinCatalog(defaultLocale, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	locale := cfg.Locale
+	if locale == "" {
+		locale = defaultLocale
+	}
+
+	found := locale == defaultLocale
+	if locale != defaultLocale {
+		lc, err := builtinCatalog(locale, c)
+		if err == nil {
+			c, found = lc, true
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	if path, ok := cfg.Catalogs[locale]; ok {
+		data, err := os.ReadFile(path) // #nosec G304 -- trusted path.
+		if err != nil {
+			return nil, fmt.Errorf("load catalog: %v", err)
+		}
+		if c, err = eventlog.ReadCatalog(locale, data, c); err != nil {
+			return nil, fmt.Errorf("load catalog: %v", err)
+		}
+		found = true
+	}
+
+	if !found {
+		return nil, fmt.Errorf("load catalog: no catalog found for locale %q", locale)
+	}
+
+	return c, nil
+}
+
+func builtinCatalog(locale string, fallback *eventlog.Catalog) (*eventlog.Catalog, error) {
+	data, err := messages.ReadFile("messages/" + locale + ".json")
+	if err != nil {
+		return nil, err
+	}
+
+	return eventlog.ReadCatalog(locale, data, fallback)
+}
+
+// mustDefaultCatalog returns the built-in catalog of the default locale, it
+// panics if the embedded catalog is not valid.
+func mustDefaultCatalog() *eventlog.Catalog {
+	c, err := builtinCatalog(defaultLocale, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
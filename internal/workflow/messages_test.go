@@ -0,0 +1,103 @@
+package workflow_test
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/fs"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+	"github.com/artefactual-sdps/preprocessing-base/internal/workflow"
+)
+
+func TestLoadCatalog(t *testing.T) {
+	t.Parallel()
+
+	dir := fs.NewDir(t, "preprocessing-messages-test",
+		fs.WithFile("fr.json", `{"bag-sip.succeeded": "Le SIP est prêt"}`),
+		fs.WithFile("it.json", `{"bag-sip.succeeded": "Il SIP è stato impacchettato"}`),
+	)
+
+	for _, tc := range []struct {
+		name    string
+		cfg     config.MessagesConfig
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "Loads the English catalog by default",
+			want: map[string]string{
+				"bag-sip.succeeded":    "SIP has been bagged",
+				"bag-sip.system-error": "System error: bagging has failed",
+			},
+		},
+		{
+			name: "Loads a built-in catalog",
+			cfg:  config.MessagesConfig{Locale: "de"},
+			want: map[string]string{
+				"bag-sip.succeeded": "Das SIP wurde im BagIt-Format verpackt",
+			},
+		},
+		{
+			name: "Overrides a built-in catalog",
+			cfg: config.MessagesConfig{
+				Locale:   "fr",
+				Catalogs: map[string]string{"fr": dir.Join("fr.json")},
+			},
+			want: map[string]string{
+				"bag-sip.succeeded":    "Le SIP est prêt",
+				"bag-sip.system-error": "Erreur système : l'empaquetage au format BagIt a échoué",
+			},
+		},
+		{
+			name: "Loads a catalog file falling back to English",
+			cfg: config.MessagesConfig{
+				Locale:   "it",
+				Catalogs: map[string]string{"it": dir.Join("it.json")},
+			},
+			want: map[string]string{
+				"bag-sip.succeeded":    "Il SIP è stato impacchettato",
+				"bag-sip.system-error": "System error: bagging has failed",
+			},
+		},
+		{
+			name:    "Errors if the locale has no catalog",
+			cfg:     config.MessagesConfig{Locale: "it"},
+			wantErr: `load catalog: no catalog found for locale "it"`,
+		},
+		{
+			name: "Errors if the catalog file doesn't exist",
+			cfg: config.MessagesConfig{
+				Locale:   "fr",
+				Catalogs: map[string]string{"fr": "/missing/fr.json"},
+			},
+			wantErr: "load catalog: open /missing/fr.json: no such file or directory",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, err := workflow.LoadCatalog(tc.cfg)
+			if tc.wantErr != "" {
+				assert.Error(t, err, tc.wantErr)
+				return
+			}
+			assert.NilError(t, err)
+
+			for key, want := range tc.want {
+				assert.Equal(t, c.Render(key, nil), want)
+			}
+		})
+	}
+}
+
+func TestBuiltinLocales(t *testing.T) {
+	t.Parallel()
+
+	// The locales validated by the configuration have a built-in catalog.
+	for _, locale := range config.Locales {
+		c, err := workflow.LoadCatalog(config.MessagesConfig{Locale: locale})
+		assert.NilError(t, err)
+		assert.Equal(t, c.Locale(), locale)
+	}
+}
@@ -0,0 +1,74 @@
+package workflow
+
+import (
+	"github.com/artefactual-sdps/preprocessing-base/internal/enums"
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
+)
+
+type Outcome int
+
+const (
+	OutcomeSuccess Outcome = iota
+	OutcomeSystemError
+	OutcomeContentError
+	OutcomeSuccessWithWarnings
+)
+
+// severity ranks the outcomes, a higher severity takes precedence when
+// aggregating outcomes.
+func (o Outcome) severity() int {
+	switch o {
+	case OutcomeSystemError:
+		return 3
+	case OutcomeContentError:
+		return 2
+	case OutcomeSuccessWithWarnings:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// OutcomeFromEvents derives the workflow outcome from the preservation events:
+//
+//   - Any system failure results in a system error.
+//   - Otherwise, any validation failure results in a content error.
+//   - Otherwise, any warning results in a success with warnings.
+//   - Otherwise, the outcome is a success.
+func OutcomeFromEvents(events []*eventlog.Event) Outcome {
+	outcome := OutcomeSuccess
+	for _, ev := range events {
+		var o Outcome
+		switch ev.Outcome {
+		case enums.EventOutcomeSystemFailure:
+			o = OutcomeSystemError
+		case enums.EventOutcomeValidationFailure:
+			o = OutcomeContentError
+		case enums.EventOutcomeWarning:
+			o = OutcomeSuccessWithWarnings
+		default:
+			continue
+		}
+		outcome = maxOutcome(outcome, o)
+	}
+
+	return outcome
+}
+
+// batchOutcome returns the most severe outcome of the batch items.
+func batchOutcome(items []*BatchItemResult) Outcome {
+	outcome := OutcomeSuccess
+	for _, item := range items {
+		outcome = maxOutcome(outcome, item.outcome())
+	}
+
+	return outcome
+}
+
+func maxOutcome(a, b Outcome) Outcome {
+	if b.severity() > a.severity() {
+		return b
+	}
+
+	return a
+}
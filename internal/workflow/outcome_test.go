@@ -0,0 +1,72 @@
+package workflow_test
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/enums"
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
+	"github.com/artefactual-sdps/preprocessing-base/internal/workflow"
+)
+
+func TestOutcomeFromEvents(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 6, 6, 14, 48, 12, 0, time.UTC)
+	event := func() *eventlog.Event {
+		return eventlog.NewEvent(now, enums.EventTypeValidation, "test event")
+	}
+
+	for _, tc := range []struct {
+		name   string
+		events []*eventlog.Event
+		want   workflow.Outcome
+	}{
+		{
+			name: "Success without events",
+			want: workflow.OutcomeSuccess,
+		},
+		{
+			name: "Success when all events succeed",
+			events: []*eventlog.Event{
+				event().Succeed(now, "ok"),
+				event().Succeed(now, "ok"),
+			},
+			want: workflow.OutcomeSuccess,
+		},
+		{
+			name: "Success with warnings when an event has warnings",
+			events: []*eventlog.Event{
+				event().Succeed(now, "ok"),
+				event().Warn(now, "empty directories found"),
+			},
+			want: workflow.OutcomeSuccessWithWarnings,
+		},
+		{
+			name: "Content error when an event fails validation",
+			events: []*eventlog.Event{
+				event().Warn(now, "empty directories found"),
+				event().Fail(now, "invalid metadata"),
+				event().Succeed(now, "ok"),
+			},
+			want: workflow.OutcomeContentError,
+		},
+		{
+			name: "System error takes precedence over other failures",
+			events: []*eventlog.Event{
+				event().Fail(now, "invalid metadata"),
+				event().SystemFail(now, "System error: bagging has failed"),
+				event().Warn(now, "empty directories found"),
+			},
+			want: workflow.OutcomeSystemError,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, workflow.OutcomeFromEvents(tc.events), tc.want)
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package workflow
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/artefactual-sdps/temporal-activities/bagcreate"
@@ -10,63 +13,236 @@ import (
 	temporalsdk_temporal "go.temporal.io/sdk/temporal"
 	temporalsdk_workflow "go.temporal.io/sdk/workflow"
 
+	"github.com/artefactual-sdps/preprocessing-base/internal/activities/describesip"
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
 	"github.com/artefactual-sdps/preprocessing-base/internal/enums"
 	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
-)
-
-type Outcome int
-
-const (
-	OutcomeSuccess Outcome = iota
-	OutcomeSystemError
-	OutcomeContentError
+	"github.com/artefactual-sdps/preprocessing-base/internal/persistence"
+	"github.com/artefactual-sdps/preprocessing-base/internal/version"
 )
 
 type PreprocessingWorkflowParams struct {
 	RelativePath string
+
+	// DryRun runs the validation steps but doesn't modify the transfer, the
+	// steps that would modify it only report what they would do.
+	DryRun bool
+
+	// Profile selects the configuration overrides of the transfer policy,
+	// e.g. "strict" (optional).
+	Profile string `json:",omitempty"`
+}
+
+// BagCreateParams are the params of the bag-create activity. Bagit is the
+// BagIt configuration of the transfer policy, it's zero for the workflows
+// started before the policy was recorded and the bag then uses the "sha512"
+// checksum algorithm.
+type BagCreateParams struct {
+	bagcreate.Params
+	Bagit bagcreate.Config
 }
 
 type PreprocessingWorkflowResult struct {
-	Outcome           Outcome
-	RelativePath      string
+	Outcome      Outcome
+	RelativePath string
+
+	// OutputPath is the SharedPath relative path of the preprocessed SIP.
+	OutputPath string
+
+	// PackageType is the type of the preprocessed SIP, it's omitted when
+	// empty to allow decoding the results of failed workflows.
+	PackageType enums.PackageType `json:",omitempty"`
+
+	// PayloadFileCount is the number of files in the SIP payload.
+	PayloadFileCount int
+
+	// PayloadByteCount is the total size in bytes of the SIP payload.
+	PayloadByteCount int64
+
+	// ChecksumAlgorithm is the algorithm used to generate the SIP payload
+	// checksums, it's empty if the SIP is not a bag.
+	ChecksumAlgorithm string
+
+	// WorkerVersion is the version of the preprocessing worker.
+	WorkerVersion string
+
+	// Timing reports the duration of the workflow and its preservation tasks.
+	Timing *eventlog.Timing
+
 	PreservationTasks []*eventlog.Event
 }
 
-func (r *PreprocessingWorkflowResult) newEvent(ctx temporalsdk_workflow.Context, name string) *eventlog.Event {
-	ev := eventlog.NewEvent(temporalsdk_workflow.Now(ctx), name)
+func (r *PreprocessingWorkflowResult) newEvent(
+	ctx temporalsdk_workflow.Context,
+	eventType enums.EventType,
+	name string,
+) *eventlog.Event {
+	ev := eventlog.NewEvent(temporalsdk_workflow.Now(ctx), eventType, name)
 	r.PreservationTasks = append(r.PreservationTasks, ev)
 
 	return ev
 }
 
-func (r *PreprocessingWorkflowResult) systemError(
-	ctx temporalsdk_workflow.Context,
-	err error,
-	ev *eventlog.Event,
-	msg string,
-) *PreprocessingWorkflowResult {
-	logger := temporalsdk_workflow.GetLogger(ctx)
-	logger.Error("System error", "message", err.Error())
+type PreprocessingWorkflow struct {
+	sharedPath string
 
-	// Complete last preservation task event.
-	ev.Complete(
-		temporalsdk_workflow.Now(ctx),
-		enums.EventOutcomeSystemFailure,
-		"System error: %s",
-		msg,
-	)
-	r.Outcome = OutcomeSystemError
+	// eventLog and database are the worker settings used by the workflows
+	// started before they were recorded in the transfer policy.
+	eventLog config.EventLogConfig
+	database bool
 
-	return r
-}
+	// fileSink writes the JSON Lines event log, it's nil if the event log is
+	// disabled in the transfer policy.
+	fileSink *eventlog.FileSink
 
-type PreprocessingWorkflow struct {
-	sharedPath string
+	// sinks receive the preservation events as they start and complete, they
+	// are set for each workflow execution by withPolicy.
+	sinks []eventlog.Sink
+
+	// extraSinks are the sinks given to NewPreprocessingWorkflow, they
+	// receive the preservation events of all the workflow executions.
+	extraSinks []eventlog.Sink
+
+	// saveRuns reports whether the transfer policy saves the workflow run in
+	// the database.
+	saveRuns bool
+
+	// activityTimeout is the ScheduleToCloseTimeout of the workflow
+	// activities.
+	activityTimeout time.Duration
+
+	// agents are added to all the preservation events of the workflow.
+	agents []eventlog.Agent
+
+	// catalog renders the preservation event messages.
+	catalog *eventlog.Catalog
+
+	// store holds the current configuration, it resolves the transfer
+	// policies with the configuration reloaded while the worker is running.
+	store *config.Store
 }
 
-func NewPreprocessingWorkflow(sharedPath string) *PreprocessingWorkflow {
+// NewPreprocessingWorkflow returns the preprocessing workflow of the
+// configuration in store. The catalog renders the event messages, the
+// English catalog is used if it's nil. The given sinks receive the
+// preservation events, in addition to the sinks enabled in the EventLog
+// configuration recorded in the transfer policy.
+func NewPreprocessingWorkflow(
+	store *config.Store,
+	agents []eventlog.Agent,
+	catalog *eventlog.Catalog,
+	sinks ...eventlog.Sink,
+) *PreprocessingWorkflow {
+	if catalog == nil {
+		catalog = mustDefaultCatalog()
+	}
+
+	cfg := store.Load()
+
+	activityTimeout := cfg.Worker.ActivityTimeout
+	if activityTimeout == 0 {
+		activityTimeout = config.DefaultActivityTimeout
+	}
+
 	return &PreprocessingWorkflow{
-		sharedPath: sharedPath,
+		sharedPath:      cfg.SharedPath,
+		eventLog:        cfg.EventLog,
+		database:        cfg.Database.Enabled,
+		extraSinks:      sinks,
+		activityTimeout: activityTimeout,
+		agents:          agents,
+		catalog:         catalog,
+		store:           store,
+	}
+}
+
+// withPolicy returns a copy of w for a workflow execution, with the sinks and
+// the database setting of the transfer policy.
+func (w *PreprocessingWorkflow) withPolicy(policy config.Policy) *PreprocessingWorkflow {
+	c := *w
+	c.sinks = nil
+	if policy.EventLog.Enabled {
+		c.fileSink = eventlog.NewFileSink(filepath.Join(w.sharedPath, policy.EventLog.Path))
+		c.sinks = append(c.sinks, c.fileSink)
+	}
+	if policy.EventLog.SignalParent {
+		c.sinks = append(c.sinks, eventlog.NewSignalSink())
+	}
+	c.sinks = append(c.sinks, w.extraSinks...)
+	c.saveRuns = policy.Database
+
+	return &c
+}
+
+// newEvent adds a new preservation event, performed by the workflow agents,
+// to the result.
+func (w *PreprocessingWorkflow) newEvent(
+	ctx temporalsdk_workflow.Context,
+	result *PreprocessingWorkflowResult,
+	eventType enums.EventType,
+	name string,
+) *eventlog.Event {
+	ev := result.newEvent(ctx, eventType, name).AddAgents(w.agents...)
+	w.emit(ctx, result, ev, eventlog.Sink.Started)
+
+	return ev
+}
+
+// step is a unit of work of the preprocessing workflow.
+type step struct {
+	// name identifies the step, it's also used as the change ID of the
+	// version marker that gates the step.
+	name string
+
+	// payload reports whether the step processes the SIP payload files, the
+	// throughput of its events is included in the timing report.
+	payload bool
+
+	// version is the workflow version that introduced the step. Steps with
+	// temporalsdk_workflow.DefaultVersion are part of the original workflow
+	// and always run, other steps only run when the workflow history records
+	// a version marker equal or greater than version.
+	version temporalsdk_workflow.Version
+
+	// run executes the step with the transfer policy. It returns an error to
+	// stop the workflow, after recording the failure in the step event. Steps
+	// that modify the transfer must only report what they would do when
+	// params.DryRun is set.
+	run func(
+		ctx temporalsdk_workflow.Context,
+		params *PreprocessingWorkflowParams,
+		policy *config.Policy,
+		result *PreprocessingWorkflowResult,
+	) error
+}
+
+// steps returns the ordered list of preprocessing workflow steps.
+//
+// Workflow executions started by a previous version of the worker replay
+// their history against this list, so changing it needs care to keep the
+// workflow deterministic:
+//
+//   - New steps must be given a unique name and a version greater than
+//     temporalsdk_workflow.DefaultVersion (e.g. 1), they will be skipped by
+//     executions recorded before the step was added.
+//   - Changes to the behavior of an existing step must be gated inside the
+//     step with temporalsdk_workflow.GetVersion.
+//   - Steps can't be reordered or removed while executions recorded with them
+//     may still be running, the same applies to the steps selected in the
+//     configuration.
+func (w *PreprocessingWorkflow) steps() []step {
+	return []step{
+		{
+			name:    "bag-sip",
+			payload: true,
+			version: temporalsdk_workflow.DefaultVersion,
+			run:     w.bagSIP,
+		},
+		{
+			name:    "describe-sip",
+			version: 1,
+			run:     w.describeSIP,
+		},
 	}
 }
 
@@ -74,43 +250,363 @@ func (w *PreprocessingWorkflow) Execute(
 	ctx temporalsdk_workflow.Context,
 	params *PreprocessingWorkflowParams,
 ) (*PreprocessingWorkflowResult, error) {
-	var (
-		result PreprocessingWorkflowResult
-		e      error
-	)
+	var result PreprocessingWorkflowResult
 
 	logger := temporalsdk_workflow.GetLogger(ctx)
 	logger.Debug("PreprocessingWorkflow workflow running!", "params", params)
 
-	if params == nil || params.RelativePath == "" {
-		e = temporal.NewNonRetryableError(fmt.Errorf("error calling workflow with unexpected inputs"))
+	// The relative path must be inside SharedPath, e.g. not "../transfer".
+	if params == nil || !filepath.IsLocal(params.RelativePath) {
+		e := temporal.NewNonRetryableError(fmt.Errorf("error calling workflow with unexpected inputs"))
 		return nil, e
 	}
 	result.RelativePath = params.RelativePath
+	result.OutputPath = params.RelativePath
+	result.WorkerVersion = version.Long
+
+	startedAt := temporalsdk_workflow.Now(ctx)
+	policy, err := w.policy(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	w = w.withPolicy(policy)
+	w.startRun(ctx, &result, startedAt)
+
+	payloadEvents := make(map[*eventlog.Event]bool)
+	for _, s := range w.steps() {
+		if len(policy.Steps) > 0 && !slices.Contains(policy.Steps, s.name) {
+			logger.Debug("Skipping step not selected in the transfer policy", "step", s.name)
+			continue
+		}
+		if !stepEnabled(ctx, s) {
+			logger.Debug("Skipping step not recorded in workflow history", "step", s.name)
+			continue
+		}
+
+		n := len(result.PreservationTasks)
+		err := s.run(ctx, params, &policy, &result)
+		events := result.PreservationTasks[n:]
+		if s.payload && !params.DryRun {
+			for _, ev := range events {
+				payloadEvents[ev] = true
+			}
+		}
+		for _, ev := range events {
+			if !ev.CompletedAt.IsZero() {
+				w.emit(ctx, &result, ev, eventlog.Sink.Completed)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	result.Outcome = OutcomeFromEvents(result.PreservationTasks)
+	result.Timing = eventlog.NewTiming(
+		startedAt,
+		temporalsdk_workflow.Now(ctx),
+		result.PreservationTasks,
+		func(ev *eventlog.Event) int64 {
+			if payloadEvents[ev] {
+				return result.PayloadByteCount
+			}
+			return 0
+		},
+	)
+	w.logTiming(ctx, &result)
+	w.saveRun(ctx, &result)
+
+	return &result, nil
+}
+
+// policy returns the policy of the transfer, resolved from the current
+// configuration when the workflow starts. The policy is recorded in the
+// workflow history so configuration changes, or a worker started with other
+// EventLog or Database settings, don't change the activities of running
+// workflows.
+func (w *PreprocessingWorkflow) policy(
+	ctx temporalsdk_workflow.Context,
+	params *PreprocessingWorkflowParams,
+) (config.Policy, error) {
+	// Workflows started before the transfer policy was added run all the
+	// steps, regardless of the current configuration.
+	v := temporalsdk_workflow.GetVersion(ctx, "transfer-policy", temporalsdk_workflow.DefaultVersion, 2)
+	if v == temporalsdk_workflow.DefaultVersion {
+		return config.Policy{EventLog: w.eventLog, Database: w.database}, nil
+	}
+
+	var policy config.Policy
+	err := temporalsdk_workflow.SideEffect(ctx, func(ctx temporalsdk_workflow.Context) any {
+		return w.store.Load().ResolvePolicy(params.RelativePath, params.Profile)
+	}).Get(&policy)
+	if err != nil {
+		return config.Policy{}, fmt.Errorf("decode transfer policy: %v", err)
+	}
+
+	// Version 1 policies don't record the EventLog and Database settings.
+	if v == 1 {
+		policy.EventLog = w.eventLog
+		policy.Database = w.database
+	}
+
+	return policy, nil
+}
+
+// systemError logs a system error, the cause of an event system failure.
+func systemError(ctx temporalsdk_workflow.Context, err error) {
+	logger := temporalsdk_workflow.GetLogger(ctx)
+	logger.Error("System error", "message", err.Error())
+}
+
+// emit sends ev to the workflow sinks calling fn, e.g. eventlog.Sink.Started.
+// A sink failure is logged but doesn't stop the workflow.
+func (w *PreprocessingWorkflow) emit(
+	ctx temporalsdk_workflow.Context,
+	result *PreprocessingWorkflowResult,
+	ev *eventlog.Event,
+	fn func(eventlog.Sink, temporalsdk_workflow.Context, *eventlog.Record) error,
+) {
+	r := newRecord(ctx, result)
+	r.Event = ev
+	for _, sink := range w.sinks {
+		if err := fn(sink, w.withLocalActOpts(ctx), r); err != nil {
+			logger := temporalsdk_workflow.GetLogger(ctx)
+			logger.Error("Unable to emit the preservation event", "message", err.Error())
+		}
+	}
+}
+
+// logTiming appends the timing report to the JSON Lines event log, if
+// enabled in the transfer policy.
+func (w *PreprocessingWorkflow) logTiming(ctx temporalsdk_workflow.Context, result *PreprocessingWorkflowResult) {
+	if w.fileSink == nil {
+		return
+	}
+
+	// Workflows started before the timing report was added can't execute the
+	// activity when they are replayed.
+	v := temporalsdk_workflow.GetVersion(ctx, "eventlog-timing", temporalsdk_workflow.DefaultVersion, 1)
+	if v == temporalsdk_workflow.DefaultVersion {
+		return
+	}
+
+	r := newRecord(ctx, result)
+	r.Timing = result.Timing
+	if err := w.fileSink.Append(w.withLocalActOpts(ctx), []*eventlog.Record{r}); err != nil {
+		logger := temporalsdk_workflow.GetLogger(ctx)
+		logger.Error("Unable to write the event log", "message", err.Error())
+	}
+}
+
+// startRun records the workflow run in the database when it starts, if
+// enabled in the transfer policy, so running workflows can be reported.
+// saveRun updates it when the workflow completes. A failure to save the run is
+// logged but doesn't stop the workflow.
+func (w *PreprocessingWorkflow) startRun(
+	ctx temporalsdk_workflow.Context,
+	result *PreprocessingWorkflowResult,
+	startedAt time.Time,
+) {
+	if !w.saveRuns {
+		return
+	}
+
+	// Workflows started before the run was saved when it starts can't execute
+	// the activity when they are replayed.
+	v := temporalsdk_workflow.GetVersion(ctx, "persistence-start", temporalsdk_workflow.DefaultVersion, 1)
+	if v == temporalsdk_workflow.DefaultVersion {
+		return
+	}
+
+	w.executeSaveRun(ctx, &persistence.Run{
+		RelativePath:  result.RelativePath,
+		WorkerVersion: result.WorkerVersion,
+		StartedAt:     startedAt,
+	})
+}
+
+// saveRun records the workflow run and its preservation events in the
+// database, if enabled in the transfer policy. A failure to save the run is
+// logged but doesn't stop the workflow.
+func (w *PreprocessingWorkflow) saveRun(ctx temporalsdk_workflow.Context, result *PreprocessingWorkflowResult) {
+	if !w.saveRuns {
+		return
+	}
+
+	// Workflows started before the database was added can't execute the
+	// activity when they are replayed.
+	v := temporalsdk_workflow.GetVersion(ctx, "persistence", temporalsdk_workflow.DefaultVersion, 1)
+	if v == temporalsdk_workflow.DefaultVersion {
+		return
+	}
+
+	w.executeSaveRun(ctx, &persistence.Run{
+		RelativePath:  result.RelativePath,
+		Outcome:       int(result.Outcome),
+		WorkerVersion: result.WorkerVersion,
+		StartedAt:     result.Timing.StartedAt,
+		CompletedAt:   result.Timing.CompletedAt,
+		Events:        result.PreservationTasks,
+	})
+}
+
+// executeSaveRun executes the persistence-save-run activity with r,
+// identified by the workflow execution.
+func (w *PreprocessingWorkflow) executeSaveRun(ctx temporalsdk_workflow.Context, r *persistence.Run) {
+	info := temporalsdk_workflow.GetInfo(ctx)
+	r.WorkflowID = info.WorkflowExecution.ID
+	r.RunID = info.WorkflowExecution.RunID
+
+	e := temporalsdk_workflow.ExecuteActivity(
+		w.withLocalActOpts(ctx),
+		persistence.SaveRunActivityName,
+		&persistence.SaveRunActivityParams{Run: r},
+	).Get(ctx, nil)
+	if e != nil {
+		logger := temporalsdk_workflow.GetLogger(ctx)
+		logger.Error("Unable to save the workflow run", "message", e.Error())
+	}
+}
+
+// newRecord returns an event log record identifying the workflow execution.
+func newRecord(ctx temporalsdk_workflow.Context, result *PreprocessingWorkflowResult) *eventlog.Record {
+	info := temporalsdk_workflow.GetInfo(ctx)
+
+	return &eventlog.Record{
+		WorkflowID:   info.WorkflowExecution.ID,
+		RunID:        info.WorkflowExecution.RunID,
+		RelativePath: result.RelativePath,
+	}
+}
+
+// StepNames returns the names of the preprocessing workflow steps, in workflow
+// order.
+func StepNames() []string {
+	steps := (&PreprocessingWorkflow{}).steps()
+	names := make([]string, len(steps))
+	for i, s := range steps {
+		names[i] = s.name
+	}
+
+	return names
+}
+
+// CheckSteps verifies that names, the steps selected in the field
+// configuration setting, are workflow step names.
+func CheckSteps(field string, names []string) error {
+	valid := StepNames()
+
+	var errs error
+	for _, name := range names {
+		if !slices.Contains(valid, name) {
+			errs = errors.Join(errs, fmt.Errorf(
+				"%s: %q is not a workflow step (%s)", field, name, strings.Join(valid, ", "),
+			))
+		}
+	}
+
+	return errs
+}
+
+// stepEnabled reports whether s must run in the current workflow execution,
+// recording a version marker in the workflow history for versioned steps.
+func stepEnabled(ctx temporalsdk_workflow.Context, s step) bool {
+	if s.version == temporalsdk_workflow.DefaultVersion {
+		return true
+	}
+
+	v := temporalsdk_workflow.GetVersion(ctx, s.name, temporalsdk_workflow.DefaultVersion, s.version)
+
+	return v != temporalsdk_workflow.DefaultVersion
+}
+
+// bagSIP bags the SIP for Enduro processing.
+func (w *PreprocessingWorkflow) bagSIP(
+	ctx temporalsdk_workflow.Context,
+	params *PreprocessingWorkflowParams,
+	policy *config.Policy,
+	result *PreprocessingWorkflowResult,
+) error {
+	ev := w.newEvent(ctx, result, enums.EventTypePacking, "Bag SIP")
+	if params.DryRun {
+		ev.CompleteWithMessage(
+			temporalsdk_workflow.Now(ctx),
+			enums.EventOutcomeSuccess,
+			w.catalog,
+			msgBagSIPDryRun,
+			nil,
+		)
+		return nil
+	}
 
-	// Bag the SIP for Enduro processing.
-	ev := result.newEvent(ctx, "Bag SIP")
 	var createBag bagcreate.Result
-	e = temporalsdk_workflow.ExecuteActivity(
-		withLocalActOpts(ctx),
+	e := temporalsdk_workflow.ExecuteActivity(
+		w.withLocalActOpts(ctx),
 		bagcreate.Name,
-		&bagcreate.Params{
-			SourcePath: filepath.Join(w.sharedPath, params.RelativePath),
+		&BagCreateParams{
+			Params: bagcreate.Params{
+				SourcePath: filepath.Join(w.sharedPath, params.RelativePath),
+			},
+			Bagit: policy.Bagit,
 		},
 	).Get(ctx, &createBag)
 	if e != nil {
-		return result.systemError(ctx, e, ev, "bagging has failed"), nil
+		systemError(ctx, e)
+		ev.CompleteWithMessage(
+			temporalsdk_workflow.Now(ctx),
+			enums.EventOutcomeSystemFailure,
+			w.catalog,
+			msgBagSIPSystemError,
+			nil,
+		)
+		return e
+	}
+	if rel, err := filepath.Rel(w.sharedPath, createBag.BagPath); err == nil {
+		result.OutputPath = rel
+	}
+	ev.CompleteWithMessage(
+		temporalsdk_workflow.Now(ctx),
+		enums.EventOutcomeSuccess,
+		w.catalog,
+		msgBagSIPSucceeded,
+		nil,
+	)
+
+	return nil
+}
+
+// describeSIP adds the SIP package type and payload size to the result.
+func (w *PreprocessingWorkflow) describeSIP(
+	ctx temporalsdk_workflow.Context,
+	params *PreprocessingWorkflowParams,
+	policy *config.Policy,
+	result *PreprocessingWorkflowResult,
+) error {
+	var res describesip.Result
+	e := temporalsdk_workflow.ExecuteActivity(
+		w.withLocalActOpts(ctx),
+		describesip.Name,
+		&describesip.Params{Path: filepath.Join(w.sharedPath, result.OutputPath)},
+	).Get(ctx, &res)
+	if e != nil {
+		// The SIP description is informative, don't stop the workflow.
+		logger := temporalsdk_workflow.GetLogger(ctx)
+		logger.Warn("Unable to describe SIP", "message", e.Error())
+		return nil
 	}
-	ev.Succeed(temporalsdk_workflow.Now(ctx), "SIP has been bagged")
 
-	return &result, e
+	result.PackageType = res.PackageType
+	result.PayloadFileCount = res.PayloadFileCount
+	result.PayloadByteCount = res.PayloadByteCount
+	result.ChecksumAlgorithm = res.ChecksumAlgorithm
+
+	return nil
 }
 
-func withLocalActOpts(ctx temporalsdk_workflow.Context) temporalsdk_workflow.Context {
+func (w *PreprocessingWorkflow) withLocalActOpts(ctx temporalsdk_workflow.Context) temporalsdk_workflow.Context {
 	return temporalsdk_workflow.WithActivityOptions(
 		ctx,
 		temporalsdk_workflow.ActivityOptions{
-			ScheduleToCloseTimeout: 5 * time.Minute,
+			ScheduleToCloseTimeout: w.activityTimeout,
 			RetryPolicy: &temporalsdk_temporal.RetryPolicy{
 				MaximumAttempts: 1,
 			},
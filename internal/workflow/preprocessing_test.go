@@ -1,6 +1,7 @@
 package workflow_test
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"testing"
@@ -11,20 +12,32 @@ import (
 	temporalsdk_activity "go.temporal.io/sdk/activity"
 	temporalsdk_testsuite "go.temporal.io/sdk/testsuite"
 	temporalsdk_worker "go.temporal.io/sdk/worker"
+	temporalsdk_workflow "go.temporal.io/sdk/workflow"
+	"gotest.tools/v3/assert"
 
+	"github.com/artefactual-sdps/preprocessing-base/internal/activities/describesip"
 	"github.com/artefactual-sdps/preprocessing-base/internal/config"
 	"github.com/artefactual-sdps/preprocessing-base/internal/enums"
 	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog"
+	"github.com/artefactual-sdps/preprocessing-base/internal/eventlog/eventlogtest"
+	"github.com/artefactual-sdps/preprocessing-base/internal/persistence"
+	"github.com/artefactual-sdps/preprocessing-base/internal/version"
 	"github.com/artefactual-sdps/preprocessing-base/internal/workflow"
 )
 
 const sharedPath = "/shared/path/"
 
+var agents = []eventlog.Agent{
+	eventlog.SoftwareAgent("preprocessing-worker", "v1.0.0"),
+	eventlog.OrganizationAgent("Test organization"),
+}
+
 type PreprocessingTestSuite struct {
 	suite.Suite
 	temporalsdk_testsuite.WorkflowTestSuite
 
 	env      *temporalsdk_testsuite.TestWorkflowEnvironment
+	store    *config.Store
 	workflow *workflow.PreprocessingWorkflow
 }
 
@@ -34,11 +47,26 @@ func (s *PreprocessingTestSuite) SetupTest(cfg config.Configuration) {
 
 	// Register activities.
 	s.env.RegisterActivityWithOptions(
-		bagcreate.New(cfg.Bagit).Execute,
+		func(ctx context.Context, params *workflow.BagCreateParams) (*bagcreate.Result, error) {
+			return bagcreate.New(params.Bagit).Execute(ctx, &params.Params)
+		},
 		temporalsdk_activity.RegisterOptions{Name: bagcreate.Name},
 	)
+	s.env.RegisterActivityWithOptions(
+		describesip.New().Execute,
+		temporalsdk_activity.RegisterOptions{Name: describesip.Name},
+	)
+	s.env.RegisterActivityWithOptions(
+		eventlog.NewAppendActivity().Execute,
+		temporalsdk_activity.RegisterOptions{Name: eventlog.AppendActivityName},
+	)
+	s.env.RegisterActivityWithOptions(
+		persistence.NewSaveRunActivity(nil).Execute,
+		temporalsdk_activity.RegisterOptions{Name: persistence.SaveRunActivityName},
+	)
 
-	s.workflow = workflow.NewPreprocessingWorkflow(sharedPath)
+	s.store = config.NewStore(cfg)
+	s.workflow = workflow.NewPreprocessingWorkflow(s.store, agents, nil)
 }
 
 func (s *PreprocessingTestSuite) AfterTest(suiteName, testName string) {
@@ -49,20 +77,44 @@ func TestPreprocessingWorkflow(t *testing.T) {
 	suite.Run(t, new(PreprocessingTestSuite))
 }
 
+// timing returns the expected timing report of a run with a "Bag SIP" event
+// that processed the given number of payload bytes. The test environment
+// clock doesn't advance, so all the durations are zero.
+func (s *PreprocessingTestSuite) timing(bytes int64) *eventlog.Timing {
+	return &eventlog.Timing{
+		StartedAt:   s.env.Now().UTC(),
+		CompletedAt: s.env.Now().UTC(),
+		Steps:       []eventlog.StepTiming{{Name: "Bag SIP", Bytes: bytes}},
+	}
+}
+
 func (s *PreprocessingTestSuite) TestSuccess() {
 	relPath := "transfer"
-	s.SetupTest(config.Configuration{})
+	s.SetupTest(config.Configuration{SharedPath: sharedPath})
 
 	// Mock activities.
 	sessionCtx := mock.AnythingOfType("*context.timerCtx")
 	s.env.OnActivity(
 		bagcreate.Name,
 		sessionCtx,
-		&bagcreate.Params{SourcePath: filepath.Join(sharedPath, relPath)},
+		&workflow.BagCreateParams{Params: bagcreate.Params{SourcePath: filepath.Join(sharedPath, relPath)}},
 	).Return(
 		&bagcreate.Result{BagPath: filepath.Join(sharedPath, relPath)},
 		nil,
 	)
+	s.env.OnActivity(
+		describesip.Name,
+		sessionCtx,
+		&describesip.Params{Path: filepath.Join(sharedPath, relPath)},
+	).Return(
+		&describesip.Result{
+			PackageType:       enums.PackageTypeBag,
+			PayloadFileCount:  2,
+			PayloadByteCount:  38,
+			ChecksumAlgorithm: "sha512",
+		},
+		nil,
+	)
 
 	s.env.ExecuteWorkflow(
 		s.workflow.Execute,
@@ -76,15 +128,25 @@ func (s *PreprocessingTestSuite) TestSuccess() {
 	s.NoError(err)
 	s.Equal(
 		&workflow.PreprocessingWorkflowResult{
-			Outcome:      workflow.OutcomeSuccess,
-			RelativePath: relPath,
+			Outcome:           workflow.OutcomeSuccess,
+			RelativePath:      relPath,
+			OutputPath:        relPath,
+			PackageType:       enums.PackageTypeBag,
+			PayloadFileCount:  2,
+			PayloadByteCount:  38,
+			ChecksumAlgorithm: "sha512",
+			WorkerVersion:     version.Long,
+			Timing:            s.timing(38),
 			PreservationTasks: []*eventlog.Event{
 				{
 					Name:        "Bag SIP",
+					Type:        enums.EventTypePacking,
 					Message:     "SIP has been bagged",
+					MessageKey:  "bag-sip.succeeded",
 					Outcome:     enums.EventOutcomeSuccess,
 					StartedAt:   s.env.Now().UTC(),
 					CompletedAt: s.env.Now().UTC(),
+					Agents:      agents,
 				},
 			},
 		},
@@ -94,14 +156,14 @@ func (s *PreprocessingTestSuite) TestSuccess() {
 
 func (s *PreprocessingTestSuite) TestSystemError() {
 	relPath := "transfer"
-	s.SetupTest(config.Configuration{})
+	s.SetupTest(config.Configuration{SharedPath: sharedPath})
 
 	// Mock activities.
 	sessionCtx := mock.AnythingOfType("*context.timerCtx")
 	s.env.OnActivity(
 		bagcreate.Name,
 		sessionCtx,
-		&bagcreate.Params{SourcePath: filepath.Join(sharedPath, relPath)},
+		&workflow.BagCreateParams{Params: bagcreate.Params{SourcePath: filepath.Join(sharedPath, relPath)}},
 	).Return(
 		nil,
 		fmt.Errorf(
@@ -122,18 +184,555 @@ func (s *PreprocessingTestSuite) TestSystemError() {
 	s.NoError(err)
 	s.Equal(
 		&workflow.PreprocessingWorkflowResult{
-			Outcome:      workflow.OutcomeSystemError,
-			RelativePath: relPath,
+			Outcome:       workflow.OutcomeSystemError,
+			RelativePath:  relPath,
+			OutputPath:    relPath,
+			WorkerVersion: version.Long,
+			Timing:        s.timing(0),
 			PreservationTasks: []*eventlog.Event{
 				{
 					Name:        "Bag SIP",
+					Type:        enums.EventTypePacking,
 					Message:     "System error: bagging has failed",
+					MessageKey:  "bag-sip.system-error",
 					Outcome:     enums.EventOutcomeSystemFailure,
 					StartedAt:   s.env.Now().UTC(),
 					CompletedAt: s.env.Now().UTC(),
+					Agents:      agents,
 				},
 			},
 		},
 		&result,
 	)
 }
+
+func (s *PreprocessingTestSuite) TestDryRun() {
+	relPath := "transfer"
+	s.SetupTest(config.Configuration{SharedPath: sharedPath})
+
+	// Mock activities.
+	sessionCtx := mock.AnythingOfType("*context.timerCtx")
+	s.env.OnActivity(
+		describesip.Name,
+		sessionCtx,
+		&describesip.Params{Path: filepath.Join(sharedPath, relPath)},
+	).Return(
+		&describesip.Result{
+			PackageType:      enums.PackageTypeDirectory,
+			PayloadFileCount: 2,
+			PayloadByteCount: 38,
+		},
+		nil,
+	)
+
+	s.env.ExecuteWorkflow(
+		s.workflow.Execute,
+		&workflow.PreprocessingWorkflowParams{
+			RelativePath: relPath,
+			DryRun:       true,
+		},
+	)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.env.AssertNotCalled(s.T(), bagcreate.Name, mock.Anything, mock.Anything)
+
+	var result workflow.PreprocessingWorkflowResult
+	err := s.env.GetWorkflowResult(&result)
+	s.NoError(err)
+	s.Equal(
+		&workflow.PreprocessingWorkflowResult{
+			Outcome:          workflow.OutcomeSuccess,
+			RelativePath:     relPath,
+			OutputPath:       relPath,
+			PackageType:      enums.PackageTypeDirectory,
+			PayloadFileCount: 2,
+			PayloadByteCount: 38,
+			WorkerVersion:    version.Long,
+			Timing:           s.timing(0),
+			PreservationTasks: []*eventlog.Event{
+				{
+					Name:        "Bag SIP",
+					Type:        enums.EventTypePacking,
+					Message:     "Dry run: SIP would be bagged",
+					MessageKey:  "bag-sip.dry-run",
+					Outcome:     enums.EventOutcomeSuccess,
+					StartedAt:   s.env.Now().UTC(),
+					CompletedAt: s.env.Now().UTC(),
+					Agents:      agents,
+				},
+			},
+		},
+		&result,
+	)
+}
+
+func (s *PreprocessingTestSuite) TestEventLog() {
+	relPath := "transfer"
+	s.SetupTest(config.Configuration{
+		SharedPath: sharedPath,
+		EventLog: config.EventLogConfig{
+			Enabled: true,
+			Path:    "preprocessing-events.jsonl",
+		},
+	})
+
+	// Mock activities.
+	sessionCtx := mock.AnythingOfType("*context.timerCtx")
+	s.env.OnActivity(
+		bagcreate.Name,
+		sessionCtx,
+		&workflow.BagCreateParams{Params: bagcreate.Params{SourcePath: filepath.Join(sharedPath, relPath)}},
+	).Return(
+		&bagcreate.Result{BagPath: filepath.Join(sharedPath, relPath)},
+		nil,
+	)
+	s.env.OnActivity(
+		describesip.Name,
+		sessionCtx,
+		&describesip.Params{Path: filepath.Join(sharedPath, relPath)},
+	).Return(
+		&describesip.Result{PackageType: enums.PackageTypeBag, PayloadByteCount: 38},
+		nil,
+	)
+	s.env.OnActivity(
+		eventlog.AppendActivityName,
+		sessionCtx,
+		&eventlog.AppendActivityParams{
+			Path: filepath.Join(sharedPath, "preprocessing-events.jsonl"),
+			Records: []*eventlog.Record{
+				{
+					WorkflowID:   "default-test-workflow-id",
+					RunID:        "default-test-run-id",
+					RelativePath: relPath,
+					Event: &eventlog.Event{
+						Name:        "Bag SIP",
+						Type:        enums.EventTypePacking,
+						Message:     "SIP has been bagged",
+						MessageKey:  "bag-sip.succeeded",
+						Outcome:     enums.EventOutcomeSuccess,
+						StartedAt:   s.env.Now().UTC(),
+						CompletedAt: s.env.Now().UTC(),
+						Agents:      agents,
+					},
+				},
+			},
+		},
+	).Return(
+		&eventlog.AppendActivityResult{},
+		nil,
+	).Once()
+	s.env.OnActivity(
+		eventlog.AppendActivityName,
+		sessionCtx,
+		&eventlog.AppendActivityParams{
+			Path: filepath.Join(sharedPath, "preprocessing-events.jsonl"),
+			Records: []*eventlog.Record{
+				{
+					WorkflowID:   "default-test-workflow-id",
+					RunID:        "default-test-run-id",
+					RelativePath: relPath,
+					Timing:       s.timing(38),
+				},
+			},
+		},
+	).Return(
+		&eventlog.AppendActivityResult{},
+		nil,
+	).Once()
+
+	s.env.ExecuteWorkflow(
+		s.workflow.Execute,
+		&workflow.PreprocessingWorkflowParams{RelativePath: relPath},
+	)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *PreprocessingTestSuite) TestDatabase() {
+	relPath := "transfer"
+	s.SetupTest(config.Configuration{
+		SharedPath: sharedPath,
+		Database: config.DatabaseConfig{
+			Enabled: true,
+			Driver:  "sqlite",
+			DSN:     "preprocessing.db",
+		},
+	})
+
+	// Mock activities.
+	sessionCtx := mock.AnythingOfType("*context.timerCtx")
+	s.env.OnActivity(
+		bagcreate.Name,
+		sessionCtx,
+		&workflow.BagCreateParams{Params: bagcreate.Params{SourcePath: filepath.Join(sharedPath, relPath)}},
+	).Return(
+		&bagcreate.Result{BagPath: filepath.Join(sharedPath, relPath)},
+		nil,
+	)
+	s.env.OnActivity(
+		describesip.Name,
+		sessionCtx,
+		&describesip.Params{Path: filepath.Join(sharedPath, relPath)},
+	).Return(
+		&describesip.Result{PackageType: enums.PackageTypeBag},
+		nil,
+	)
+	s.env.OnActivity(
+		persistence.SaveRunActivityName,
+		sessionCtx,
+		&persistence.SaveRunActivityParams{
+			Run: &persistence.Run{
+				WorkflowID:    "default-test-workflow-id",
+				RunID:         "default-test-run-id",
+				RelativePath:  relPath,
+				WorkerVersion: version.Long,
+				StartedAt:     s.env.Now().UTC(),
+			},
+		},
+	).Return(
+		&persistence.SaveRunActivityResult{},
+		nil,
+	).Once()
+	s.env.OnActivity(
+		persistence.SaveRunActivityName,
+		sessionCtx,
+		&persistence.SaveRunActivityParams{
+			Run: &persistence.Run{
+				WorkflowID:    "default-test-workflow-id",
+				RunID:         "default-test-run-id",
+				RelativePath:  relPath,
+				Outcome:       int(workflow.OutcomeSuccess),
+				WorkerVersion: version.Long,
+				StartedAt:     s.env.Now().UTC(),
+				CompletedAt:   s.env.Now().UTC(),
+				Events: []*eventlog.Event{
+					{
+						Name:        "Bag SIP",
+						Type:        enums.EventTypePacking,
+						Message:     "SIP has been bagged",
+						MessageKey:  "bag-sip.succeeded",
+						Outcome:     enums.EventOutcomeSuccess,
+						StartedAt:   s.env.Now().UTC(),
+						CompletedAt: s.env.Now().UTC(),
+						Agents:      agents,
+					},
+				},
+			},
+		},
+	).Return(
+		&persistence.SaveRunActivityResult{},
+		nil,
+	).Once()
+
+	s.env.ExecuteWorkflow(
+		s.workflow.Execute,
+		&workflow.PreprocessingWorkflowParams{RelativePath: relPath},
+	)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *PreprocessingTestSuite) TestSignalParent() {
+	relPath := "transfer"
+	cfg := config.Configuration{
+		SharedPath: sharedPath,
+		EventLog:   config.EventLogConfig{SignalParent: true},
+	}
+	s.SetupTest(cfg)
+
+	mem := eventlogtest.NewMemorySink()
+	s.workflow = workflow.NewPreprocessingWorkflow(config.NewStore(cfg), agents, nil, mem)
+	s.env.RegisterWorkflowWithOptions(
+		s.workflow.Execute,
+		temporalsdk_workflow.RegisterOptions{Name: "preprocessing"},
+	)
+
+	// Mock activities.
+	sessionCtx := mock.AnythingOfType("*context.timerCtx")
+	s.env.OnActivity(
+		bagcreate.Name,
+		sessionCtx,
+		&workflow.BagCreateParams{Params: bagcreate.Params{SourcePath: filepath.Join(sharedPath, relPath)}},
+	).Return(
+		&bagcreate.Result{BagPath: filepath.Join(sharedPath, relPath)},
+		nil,
+	)
+	s.env.OnActivity(
+		describesip.Name,
+		sessionCtx,
+		&describesip.Params{Path: filepath.Join(sharedPath, relPath)},
+	).Return(
+		&describesip.Result{PackageType: enums.PackageTypeBag},
+		nil,
+	)
+
+	// The parent workflow receives the signals sent by the child workflow.
+	var (
+		result  workflow.PreprocessingWorkflowResult
+		signals []*eventlog.Record
+	)
+	s.env.ExecuteWorkflow(func(ctx temporalsdk_workflow.Context) error {
+		err := temporalsdk_workflow.ExecuteChildWorkflow(
+			ctx,
+			"preprocessing",
+			&workflow.PreprocessingWorkflowParams{RelativePath: relPath},
+		).Get(ctx, &result)
+		if err != nil {
+			return err
+		}
+
+		ch := temporalsdk_workflow.GetSignalChannel(ctx, eventlog.SignalName)
+		for {
+			var r eventlog.Record
+			if !ch.ReceiveAsync(&r) {
+				return nil
+			}
+			signals = append(signals, &r)
+		}
+	})
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+
+	// The event is signaled when it starts and when it completes.
+	s.Len(signals, 2)
+	s.Equal(relPath, signals[0].RelativePath)
+	s.Equal(enums.EventOutcomeUnspecified, signals[0].Event.Outcome)
+	s.Equal(result.PreservationTasks[0], signals[1].Event)
+	s.Equal(result.PreservationTasks, mem.Events())
+}
+
+func (s *PreprocessingTestSuite) TestInvalidParams() {
+	for _, relPath := range []string{"", "../transfer", "/shared/path/transfer"} {
+		s.Run(relPath, func() {
+			s.SetupTest(config.Configuration{SharedPath: sharedPath})
+
+			s.env.ExecuteWorkflow(
+				s.workflow.Execute,
+				&workflow.PreprocessingWorkflowParams{RelativePath: relPath},
+			)
+
+			s.True(s.env.IsWorkflowCompleted())
+			s.ErrorContains(s.env.GetWorkflowError(), "error calling workflow with unexpected inputs")
+		})
+	}
+}
+
+func (s *PreprocessingTestSuite) TestSteps() {
+	relPath := "transfer"
+	s.SetupTest(config.Configuration{
+		SharedPath: sharedPath,
+		Steps:      []string{"describe-sip"},
+	})
+
+	// The bag-sip step doesn't run, bagcreate is not mocked.
+	sessionCtx := mock.AnythingOfType("*context.timerCtx")
+	s.env.OnActivity(
+		describesip.Name,
+		sessionCtx,
+		&describesip.Params{Path: filepath.Join(sharedPath, relPath)},
+	).Return(
+		&describesip.Result{PackageType: enums.PackageTypeDirectory},
+		nil,
+	)
+
+	s.env.ExecuteWorkflow(
+		s.workflow.Execute,
+		&workflow.PreprocessingWorkflowParams{RelativePath: relPath},
+	)
+
+	s.True(s.env.IsWorkflowCompleted())
+
+	var result workflow.PreprocessingWorkflowResult
+	err := s.env.GetWorkflowResult(&result)
+	s.NoError(err)
+	s.Equal(enums.PackageTypeDirectory, result.PackageType)
+	s.Empty(result.PreservationTasks)
+}
+
+func (s *PreprocessingTestSuite) TestReloadedSteps() {
+	relPath := "transfer"
+	cfg := config.Configuration{
+		SharedPath: sharedPath,
+		Steps:      []string{"bag-sip"},
+	}
+	s.SetupTest(cfg)
+
+	// The workflow runs the steps of the configuration reloaded after the
+	// worker started.
+	cfg.Steps = []string{"describe-sip"}
+	s.Empty(s.store.Reload(cfg))
+
+	// The bag-sip step doesn't run, bagcreate is not mocked.
+	sessionCtx := mock.AnythingOfType("*context.timerCtx")
+	s.env.OnActivity(
+		describesip.Name,
+		sessionCtx,
+		&describesip.Params{Path: filepath.Join(sharedPath, relPath)},
+	).Return(
+		&describesip.Result{PackageType: enums.PackageTypeDirectory},
+		nil,
+	)
+
+	s.env.ExecuteWorkflow(
+		s.workflow.Execute,
+		&workflow.PreprocessingWorkflowParams{RelativePath: relPath},
+	)
+
+	s.True(s.env.IsWorkflowCompleted())
+
+	var result workflow.PreprocessingWorkflowResult
+	err := s.env.GetWorkflowResult(&result)
+	s.NoError(err)
+	s.Equal(enums.PackageTypeDirectory, result.PackageType)
+	s.Empty(result.PreservationTasks)
+}
+
+func (s *PreprocessingTestSuite) TestPolicy() {
+	relPath := "donor-a/transfer"
+	s.SetupTest(config.Configuration{
+		SharedPath: sharedPath,
+		Overrides: []config.Override{
+			{
+				RelativePath: "donor-a/*",
+				Steps:        []string{"bag-sip"},
+			},
+			{
+				Profile: "strict",
+				Bagit:   bagcreate.Config{ChecksumAlgorithm: "sha256"},
+			},
+		},
+	})
+
+	// The describe-sip step doesn't run, describesip is not mocked.
+	sessionCtx := mock.AnythingOfType("*context.timerCtx")
+	s.env.OnActivity(
+		bagcreate.Name,
+		sessionCtx,
+		&workflow.BagCreateParams{
+			Params: bagcreate.Params{SourcePath: filepath.Join(sharedPath, relPath)},
+			Bagit:  bagcreate.Config{ChecksumAlgorithm: "sha256"},
+		},
+	).Return(
+		&bagcreate.Result{BagPath: filepath.Join(sharedPath, relPath)},
+		nil,
+	)
+
+	s.env.ExecuteWorkflow(
+		s.workflow.Execute,
+		&workflow.PreprocessingWorkflowParams{RelativePath: relPath, Profile: "strict"},
+	)
+
+	s.True(s.env.IsWorkflowCompleted())
+
+	var result workflow.PreprocessingWorkflowResult
+	err := s.env.GetWorkflowResult(&result)
+	s.NoError(err)
+	s.Equal(workflow.OutcomeSuccess, result.Outcome)
+	s.Len(result.PreservationTasks, 1)
+	s.Empty(result.PackageType)
+}
+
+func (s *PreprocessingTestSuite) TestPolicyBagit() {
+	relPath := "transfer"
+	s.SetupTest(config.Configuration{
+		SharedPath: sharedPath,
+		Steps:      []string{"bag-sip"},
+		Bagit:      bagcreate.Config{ChecksumAlgorithm: "md5"},
+	})
+
+	// The bag is created with the Bagit configuration recorded in the transfer
+	// policy, not with the configuration of the worker running the activity.
+	sessionCtx := mock.AnythingOfType("*context.timerCtx")
+	s.env.OnActivity(
+		bagcreate.Name,
+		sessionCtx,
+		&workflow.BagCreateParams{
+			Params: bagcreate.Params{SourcePath: filepath.Join(sharedPath, relPath)},
+			Bagit:  bagcreate.Config{ChecksumAlgorithm: "md5"},
+		},
+	).Return(
+		&bagcreate.Result{BagPath: filepath.Join(sharedPath, relPath)},
+		nil,
+	)
+
+	s.env.ExecuteWorkflow(
+		s.workflow.Execute,
+		&workflow.PreprocessingWorkflowParams{RelativePath: relPath},
+	)
+
+	s.True(s.env.IsWorkflowCompleted())
+
+	var result workflow.PreprocessingWorkflowResult
+	err := s.env.GetWorkflowResult(&result)
+	s.NoError(err)
+	s.Equal(workflow.OutcomeSuccess, result.Outcome)
+	s.Len(result.PreservationTasks, 1)
+}
+
+func (s *PreprocessingTestSuite) TestReloadedPolicy() {
+	relPath := "donor-a/transfer"
+	cfg := config.Configuration{
+		SharedPath: sharedPath,
+		Overrides: []config.Override{
+			{
+				RelativePath: "donor-a/*",
+				Steps:        []string{"describe-sip"},
+			},
+		},
+	}
+	s.SetupTest(cfg)
+
+	// The workflow resolves its policy with the overrides reloaded after the
+	// worker started.
+	cfg.Overrides = []config.Override{
+		{
+			RelativePath: "donor-a/*",
+			Steps:        []string{"bag-sip"},
+			Bagit:        bagcreate.Config{ChecksumAlgorithm: "sha256"},
+		},
+	}
+	s.Empty(s.store.Reload(cfg))
+
+	// The describe-sip step doesn't run, describesip is not mocked.
+	sessionCtx := mock.AnythingOfType("*context.timerCtx")
+	s.env.OnActivity(
+		bagcreate.Name,
+		sessionCtx,
+		&workflow.BagCreateParams{
+			Params: bagcreate.Params{SourcePath: filepath.Join(sharedPath, relPath)},
+			Bagit:  bagcreate.Config{ChecksumAlgorithm: "sha256"},
+		},
+	).Return(
+		&bagcreate.Result{BagPath: filepath.Join(sharedPath, relPath)},
+		nil,
+	)
+
+	s.env.ExecuteWorkflow(
+		s.workflow.Execute,
+		&workflow.PreprocessingWorkflowParams{RelativePath: relPath},
+	)
+
+	s.True(s.env.IsWorkflowCompleted())
+
+	var result workflow.PreprocessingWorkflowResult
+	err := s.env.GetWorkflowResult(&result)
+	s.NoError(err)
+	s.Equal(workflow.OutcomeSuccess, result.Outcome)
+	s.Len(result.PreservationTasks, 1)
+	s.Empty(result.PackageType)
+}
+
+func TestCheckSteps(t *testing.T) {
+	t.Parallel()
+
+	assert.NilError(t, workflow.CheckSteps("Steps", nil))
+	assert.NilError(t, workflow.CheckSteps("Steps", workflow.StepNames()))
+	assert.Error(
+		t,
+		workflow.CheckSteps("Profiles[0].Steps", []string{"bag-sip", "virus-scan"}),
+		`Profiles[0].Steps: "virus-scan" is not a workflow step (bag-sip, describe-sip)`,
+	)
+}
@@ -0,0 +1,75 @@
+package workflow_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	temporalsdk_worker "go.temporal.io/sdk/worker"
+	temporalsdk_workflow "go.temporal.io/sdk/workflow"
+	"gotest.tools/v3/assert"
+
+	"github.com/artefactual-sdps/preprocessing-base/internal/config"
+	"github.com/artefactual-sdps/preprocessing-base/internal/workflow"
+)
+
+var replayConfig = config.Configuration{
+	SharedPath: sharedPath,
+	EventLog:   config.EventLogConfig{Enabled: true, Path: "preprocessing-events.jsonl"},
+	Database:   config.DatabaseConfig{Enabled: true},
+}
+
+// TestReplay replays the workflow histories exported to testdata against the
+// current workflow code to detect nondeterministic changes. Add the history
+// of a workflow execution to testdata (e.g. `temporal workflow show
+// --workflow-id <id> --output json > testdata/<name>.json`) before changing the
+// workflow steps.
+//
+// Most histories are recorded with the event log and the database enabled, the
+// workflow is replayed with the same configuration so the activities gated by
+// their version markers are executed. Histories recorded before the version
+// markers were added, e.g. preprocessing_success.json, don't execute them.
+//
+// The histories are also replayed with a different configuration of the
+// settings that workers can reload, running workflows must replay the steps
+// they recorded. The histories that record the EventLog and Database settings
+// in the transfer policy, preprocessing_sinks.json and
+// preprocessing_no_sinks.json, must replay with any worker settings.
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	otherSteps := replayConfig
+	otherSteps.Steps = []string{"describe-sip"}
+
+	noSinks := replayConfig
+	noSinks.EventLog = config.EventLogConfig{SignalParent: true}
+	noSinks.Database = config.DatabaseConfig{}
+
+	for _, tc := range []struct {
+		name    string
+		cfg     config.Configuration
+		pattern string
+	}{
+		{name: "recorded configuration", cfg: replayConfig, pattern: "*.json"},
+		{name: "other steps", cfg: otherSteps, pattern: "*.json"},
+		{name: "other sinks", cfg: noSinks, pattern: "preprocessing_*sinks.json"},
+	} {
+		histories, err := filepath.Glob(filepath.Join("testdata", tc.pattern))
+		assert.NilError(t, err)
+		assert.Assert(t, len(histories) > 0, "no workflow histories found in testdata")
+
+		for _, h := range histories {
+			t.Run(tc.name+"/"+filepath.Base(h), func(t *testing.T) {
+				t.Parallel()
+
+				replayer := temporalsdk_worker.NewWorkflowReplayer()
+				replayer.RegisterWorkflowWithOptions(
+					workflow.NewPreprocessingWorkflow(config.NewStore(tc.cfg), nil, nil).Execute,
+					temporalsdk_workflow.RegisterOptions{Name: "preprocessing"},
+				)
+
+				err := replayer.ReplayWorkflowHistoryFromJSONFile(nil, h)
+				assert.NilError(t, err)
+			})
+		}
+	}
+}